@@ -3,6 +3,8 @@ package sevalla
 import (
 	"context"
 	"fmt"
+	"iter"
+	"time"
 )
 
 // ApplicationsService handles communication with the application-related
@@ -57,8 +59,66 @@ type CDNSettingsRequest struct {
 	Enabled bool `json:"enabled"`
 }
 
+// Validate checks the request for errors the API would reject
+func (r *CreateApplicationRequest) Validate() error {
+	if r == nil {
+		return nilRequest()
+	}
+
+	var v validator
+	v.required("Name", "name", r.Name)
+	v.repositoryURL("RepositoryURL", "repository_url", r.RepositoryURL)
+	v.region("Region", "location", r.Region)
+	v.plan("Plan", "pod_size", r.Plan)
+	v.min("Replicas", "replicas", r.Replicas, 0)
+	if r.Port != 0 {
+		v.port("Port", "port", r.Port)
+	}
+	return v.err()
+}
+
+// Validate checks the request for errors the API would reject
+func (r *UpdateApplicationRequest) Validate() error {
+	if r == nil {
+		return nilRequest()
+	}
+
+	var v validator
+	v.notEmpty("Name", "name", r.Name)
+	v.notEmpty("Branch", "branch", r.Branch)
+	if r.Plan != nil {
+		v.required("Plan", "pod_size", string(*r.Plan))
+		v.plan("Plan", "pod_size", *r.Plan)
+	}
+	if r.Replicas != nil {
+		v.min("Replicas", "replicas", *r.Replicas, 0)
+	}
+	if r.Port != nil {
+		v.port("Port", "port", *r.Port)
+	}
+	return v.err()
+}
+
+// Validate checks the request for errors the API would reject
+func (r *ScaleApplicationRequest) Validate() error {
+	if r == nil {
+		return nilRequest()
+	}
+
+	var v validator
+	v.min("Replicas", "replicas", r.Replicas, 0)
+	if r.Plan != nil {
+		v.required("Plan", "pod_size", string(*r.Plan))
+		v.plan("Plan", "pod_size", *r.Plan)
+	}
+	return v.err()
+}
+
 // List returns all applications
 func (s *ApplicationsService) List(ctx context.Context, opts *ListOptions) ([]*Application, *Response, error) {
+	ctx, span := s.client.startSpan(ctx, "Applications.List")
+	defer span.End()
+
 	u := "applications"
 	req, err := s.client.NewRequestWithQuery(ctx, "GET", u, opts)
 	if err != nil {
@@ -74,8 +134,21 @@ func (s *ApplicationsService) List(ctx context.Context, opts *ListOptions) ([]*A
 	return apps, resp, nil
 }
 
+// ListIter returns an iterator over all applications across all pages
+func (s *ApplicationsService) ListIter(ctx context.Context, opts *ListOptions) iter.Seq2[*Application, error] {
+	return paginate(ctx, s.client, "Applications.ListIter", opts, s.List)
+}
+
+// ListAll returns all applications across all pages
+func (s *ApplicationsService) ListAll(ctx context.Context, opts *ListOptions) ([]*Application, error) {
+	return collect(paginate(ctx, s.client, "Applications.ListAll", opts, s.List))
+}
+
 // Get returns a single application by ID
 func (s *ApplicationsService) Get(ctx context.Context, id string) (*Application, *Response, error) {
+	ctx, span := s.client.startSpan(ctx, "Applications.Get", Attribute{Key: attrApplicationID, Value: id})
+	defer span.End()
+
 	u := fmt.Sprintf("applications/%s", id)
 	req, err := s.client.NewRequest(ctx, "GET", u, nil)
 	if err != nil {
@@ -93,6 +166,9 @@ func (s *ApplicationsService) Get(ctx context.Context, id string) (*Application,
 
 // Create creates a new application
 func (s *ApplicationsService) Create(ctx context.Context, createReq *CreateApplicationRequest) (*Application, *Response, error) {
+	ctx, span := s.client.startSpan(ctx, "Applications.Create")
+	defer span.End()
+
 	u := "applications"
 	req, err := s.client.NewRequest(ctx, "POST", u, createReq)
 	if err != nil {
@@ -110,6 +186,9 @@ func (s *ApplicationsService) Create(ctx context.Context, createReq *CreateAppli
 
 // Update updates an existing application
 func (s *ApplicationsService) Update(ctx context.Context, id string, updateReq *UpdateApplicationRequest) (*Application, *Response, error) {
+	ctx, span := s.client.startSpan(ctx, "Applications.Update", Attribute{Key: attrApplicationID, Value: id})
+	defer span.End()
+
 	u := fmt.Sprintf("applications/%s", id)
 	req, err := s.client.NewRequest(ctx, "PATCH", u, updateReq)
 	if err != nil {
@@ -125,8 +204,36 @@ func (s *ApplicationsService) Update(ctx context.Context, id string, updateReq *
 	return app, resp, nil
 }
 
+// Modify applies a read-modify-write change to an application. It fetches
+// the application, passes it to mutate to build the update, and sends the
+// update conditionally on the fetched version. When another change lands in
+// between, the application is fetched again and mutate called with the new
+// version, up to 5 times. A nil update request from mutate leaves the
+// application untouched.
+func (s *ApplicationsService) Modify(ctx context.Context, id string, mutate func(app *Application) (*UpdateApplicationRequest, error)) (*Application, *Response, error) {
+	ctx, span := s.client.startSpan(ctx, "Applications.Modify", Attribute{Key: attrApplicationID, Value: id})
+	defer span.End()
+
+	app, resp, err := modify(ctx,
+		func(ctx context.Context) (*Application, *Response, error) { return s.Get(ctx, id) },
+		mutate,
+		func(ctx context.Context, updateReq *UpdateApplicationRequest) (*Application, *Response, error) {
+			return s.Update(ctx, id, updateReq)
+		},
+		func(app *Application) time.Time { return app.UpdatedAt },
+	)
+	if err != nil {
+		span.RecordError(err)
+	}
+
+	return app, resp, err
+}
+
 // Delete deletes an application
 func (s *ApplicationsService) Delete(ctx context.Context, id string) (*Response, error) {
+	ctx, span := s.client.startSpan(ctx, "Applications.Delete", Attribute{Key: attrApplicationID, Value: id})
+	defer span.End()
+
 	u := fmt.Sprintf("applications/%s", id)
 	req, err := s.client.NewRequest(ctx, "DELETE", u, nil)
 	if err != nil {
@@ -138,6 +245,9 @@ func (s *ApplicationsService) Delete(ctx context.Context, id string) (*Response,
 
 // Scale scales an application's resources
 func (s *ApplicationsService) Scale(ctx context.Context, id string, scaleReq *ScaleApplicationRequest) (*Application, *Response, error) {
+	ctx, span := s.client.startSpan(ctx, "Applications.Scale", Attribute{Key: attrApplicationID, Value: id})
+	defer span.End()
+
 	u := fmt.Sprintf("applications/%s/scale", id)
 	req, err := s.client.NewRequest(ctx, "POST", u, scaleReq)
 	if err != nil {
@@ -155,6 +265,9 @@ func (s *ApplicationsService) Scale(ctx context.Context, id string, scaleReq *Sc
 
 // Deploy triggers a new deployment for an application
 func (s *ApplicationsService) Deploy(ctx context.Context, id string) (*Deployment, *Response, error) {
+	ctx, span := s.client.startSpan(ctx, "Applications.Deploy", Attribute{Key: attrApplicationID, Value: id})
+	defer span.End()
+
 	u := fmt.Sprintf("applications/%s/deployments", id)
 	req, err := s.client.NewRequest(ctx, "POST", u, nil)
 	if err != nil {
@@ -172,6 +285,9 @@ func (s *ApplicationsService) Deploy(ctx context.Context, id string) (*Deploymen
 
 // Restart restarts an application
 func (s *ApplicationsService) Restart(ctx context.Context, id string) (*Response, error) {
+	ctx, span := s.client.startSpan(ctx, "Applications.Restart", Attribute{Key: attrApplicationID, Value: id})
+	defer span.End()
+
 	u := fmt.Sprintf("applications/%s/restart", id)
 	req, err := s.client.NewRequest(ctx, "POST", u, nil)
 	if err != nil {
@@ -183,6 +299,9 @@ func (s *ApplicationsService) Restart(ctx context.Context, id string) (*Response
 
 // Stop stops an application
 func (s *ApplicationsService) Stop(ctx context.Context, id string) (*Response, error) {
+	ctx, span := s.client.startSpan(ctx, "Applications.Stop", Attribute{Key: attrApplicationID, Value: id})
+	defer span.End()
+
 	u := fmt.Sprintf("applications/%s/stop", id)
 	req, err := s.client.NewRequest(ctx, "POST", u, nil)
 	if err != nil {
@@ -194,6 +313,9 @@ func (s *ApplicationsService) Stop(ctx context.Context, id string) (*Response, e
 
 // Start starts a stopped application
 func (s *ApplicationsService) Start(ctx context.Context, id string) (*Response, error) {
+	ctx, span := s.client.startSpan(ctx, "Applications.Start", Attribute{Key: attrApplicationID, Value: id})
+	defer span.End()
+
 	u := fmt.Sprintf("applications/%s/start", id)
 	req, err := s.client.NewRequest(ctx, "POST", u, nil)
 	if err != nil {
@@ -205,6 +327,9 @@ func (s *ApplicationsService) Start(ctx context.Context, id string) (*Response,
 
 // GetLogs retrieves application logs
 func (s *ApplicationsService) GetLogs(ctx context.Context, id string, lines int) (string, *Response, error) {
+	ctx, span := s.client.startSpan(ctx, "Applications.GetLogs", Attribute{Key: attrApplicationID, Value: id})
+	defer span.End()
+
 	u := fmt.Sprintf("applications/%s/logs", id)
 	if lines > 0 {
 		u = fmt.Sprintf("%s?lines=%d", u, lines)
@@ -226,8 +351,17 @@ func (s *ApplicationsService) GetLogs(ctx context.Context, id string, lines int)
 	return result.Logs, resp, nil
 }
 
+// TailLogs streams application logs as they are written
+func (s *ApplicationsService) TailLogs(ctx context.Context, id string, opts *TailLogsOptions) (*LogStream, error) {
+	u := fmt.Sprintf("applications/%s/logs/stream", id)
+	return s.client.tailLogs(ctx, "Applications.TailLogs", u, opts, Attribute{Key: attrApplicationID, Value: id})
+}
+
 // ListDeployments lists all deployments for an application
 func (s *ApplicationsService) ListDeployments(ctx context.Context, id string, opts *ListOptions) ([]*Deployment, *Response, error) {
+	ctx, span := s.client.startSpan(ctx, "Applications.ListDeployments", Attribute{Key: attrApplicationID, Value: id})
+	defer span.End()
+
 	u := fmt.Sprintf("applications/%s/deployments", id)
 	req, err := s.client.NewRequestWithQuery(ctx, "GET", u, opts)
 	if err != nil {
@@ -243,8 +377,25 @@ func (s *ApplicationsService) ListDeployments(ctx context.Context, id string, op
 	return deployments, resp, nil
 }
 
+// ListDeploymentsIter returns an iterator over all deployments for an application across all pages
+func (s *ApplicationsService) ListDeploymentsIter(ctx context.Context, id string, opts *ListOptions) iter.Seq2[*Deployment, error] {
+	return paginate(ctx, s.client, "Applications.ListDeploymentsIter", opts, func(ctx context.Context, opts *ListOptions) ([]*Deployment, *Response, error) {
+		return s.ListDeployments(ctx, id, opts)
+	}, Attribute{Key: attrApplicationID, Value: id})
+}
+
+// ListAllDeployments returns all deployments for an application across all pages
+func (s *ApplicationsService) ListAllDeployments(ctx context.Context, id string, opts *ListOptions) ([]*Deployment, error) {
+	return collect(paginate(ctx, s.client, "Applications.ListAllDeployments", opts, func(ctx context.Context, opts *ListOptions) ([]*Deployment, *Response, error) {
+		return s.ListDeployments(ctx, id, opts)
+	}, Attribute{Key: attrApplicationID, Value: id}))
+}
+
 // GetDeployment gets a specific deployment for an application
 func (s *ApplicationsService) GetDeployment(ctx context.Context, appID, deploymentID string) (*Deployment, *Response, error) {
+	ctx, span := s.client.startSpan(ctx, "Applications.GetDeployment", Attribute{Key: attrApplicationID, Value: appID}, Attribute{Key: attrDeploymentID, Value: deploymentID})
+	defer span.End()
+
 	u := fmt.Sprintf("applications/%s/deployments/%s", appID, deploymentID)
 	req, err := s.client.NewRequest(ctx, "GET", u, nil)
 	if err != nil {
@@ -262,6 +413,9 @@ func (s *ApplicationsService) GetDeployment(ctx context.Context, appID, deployme
 
 // CancelDeployment cancels a deployment
 func (s *ApplicationsService) CancelDeployment(ctx context.Context, appID, deploymentID string) (*Response, error) {
+	ctx, span := s.client.startSpan(ctx, "Applications.CancelDeployment", Attribute{Key: attrApplicationID, Value: appID}, Attribute{Key: attrDeploymentID, Value: deploymentID})
+	defer span.End()
+
 	u := fmt.Sprintf("applications/%s/deployments/%s/cancel", appID, deploymentID)
 	req, err := s.client.NewRequest(ctx, "POST", u, nil)
 	if err != nil {
@@ -273,6 +427,9 @@ func (s *ApplicationsService) CancelDeployment(ctx context.Context, appID, deplo
 
 // AddCustomDomain adds a custom domain to an application
 func (s *ApplicationsService) AddCustomDomain(ctx context.Context, id string, domain string) (*Response, error) {
+	ctx, span := s.client.startSpan(ctx, "Applications.AddCustomDomain", Attribute{Key: attrApplicationID, Value: id})
+	defer span.End()
+
 	u := fmt.Sprintf("applications/%s/domains", id)
 	req, err := s.client.NewRequest(ctx, "POST", u, &AddDomainRequest{Domain: domain})
 	if err != nil {
@@ -284,6 +441,9 @@ func (s *ApplicationsService) AddCustomDomain(ctx context.Context, id string, do
 
 // RemoveCustomDomain removes a custom domain from an application
 func (s *ApplicationsService) RemoveCustomDomain(ctx context.Context, id string, domain string) (*Response, error) {
+	ctx, span := s.client.startSpan(ctx, "Applications.RemoveCustomDomain", Attribute{Key: attrApplicationID, Value: id})
+	defer span.End()
+
 	u := fmt.Sprintf("applications/%s/domains/%s", id, domain)
 	req, err := s.client.NewRequest(ctx, "DELETE", u, nil)
 	if err != nil {
@@ -295,6 +455,9 @@ func (s *ApplicationsService) RemoveCustomDomain(ctx context.Context, id string,
 
 // UpdateCDNSettings updates CDN settings for an application
 func (s *ApplicationsService) UpdateCDNSettings(ctx context.Context, id string, enabled bool) (*Response, error) {
+	ctx, span := s.client.startSpan(ctx, "Applications.UpdateCDNSettings", Attribute{Key: attrApplicationID, Value: id})
+	defer span.End()
+
 	u := fmt.Sprintf("applications/%s/cdn", id)
 	req, err := s.client.NewRequest(ctx, "PUT", u, &CDNSettingsRequest{Enabled: enabled})
 	if err != nil {
@@ -306,6 +469,9 @@ func (s *ApplicationsService) UpdateCDNSettings(ctx context.Context, id string,
 
 // GetUsage retrieves usage metrics for an application
 func (s *ApplicationsService) GetUsage(ctx context.Context, id string, period string) (*Usage, *Response, error) {
+	ctx, span := s.client.startSpan(ctx, "Applications.GetUsage", Attribute{Key: attrApplicationID, Value: id})
+	defer span.End()
+
 	u := fmt.Sprintf("applications/%s/usage", id)
 	if period != "" {
 		u = fmt.Sprintf("%s?period=%s", u, period)
@@ -327,6 +493,9 @@ func (s *ApplicationsService) GetUsage(ctx context.Context, id string, period st
 
 // SetEnvironmentVariables sets environment variables for an application
 func (s *ApplicationsService) SetEnvironmentVariables(ctx context.Context, id string, vars map[string]string) (*Response, error) {
+	ctx, span := s.client.startSpan(ctx, "Applications.SetEnvironmentVariables", Attribute{Key: attrApplicationID, Value: id})
+	defer span.End()
+
 	u := fmt.Sprintf("applications/%s/env", id)
 	req, err := s.client.NewRequest(ctx, "PUT", u, vars)
 	if err != nil {
@@ -336,15 +505,19 @@ func (s *ApplicationsService) SetEnvironmentVariables(ctx context.Context, id st
 	return s.client.Do(req, nil)
 }
 
-// GetEnvironmentVariables gets environment variables for an application
-func (s *ApplicationsService) GetEnvironmentVariables(ctx context.Context, id string) (map[string]string, *Response, error) {
+// GetEnvironmentVariables gets environment variables for an application.
+// Use Reveal or RevealSecrets to read the values.
+func (s *ApplicationsService) GetEnvironmentVariables(ctx context.Context, id string) (map[string]Secret, *Response, error) {
+	ctx, span := s.client.startSpan(ctx, "Applications.GetEnvironmentVariables", Attribute{Key: attrApplicationID, Value: id})
+	defer span.End()
+
 	u := fmt.Sprintf("applications/%s/env", id)
 	req, err := s.client.NewRequest(ctx, "GET", u, nil)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	vars := make(map[string]string)
+	vars := make(map[string]Secret)
 	resp, err := s.client.Do(req, &vars)
 	if err != nil {
 		return nil, resp, err
@@ -355,6 +528,9 @@ func (s *ApplicationsService) GetEnvironmentVariables(ctx context.Context, id st
 
 // Rollback rolls back to a previous deployment
 func (s *ApplicationsService) Rollback(ctx context.Context, appID, deploymentID string) (*Deployment, *Response, error) {
+	ctx, span := s.client.startSpan(ctx, "Applications.Rollback", Attribute{Key: attrApplicationID, Value: appID}, Attribute{Key: attrDeploymentID, Value: deploymentID})
+	defer span.End()
+
 	u := fmt.Sprintf("applications/%s/rollback/%s", appID, deploymentID)
 	req, err := s.client.NewRequest(ctx, "POST", u, nil)
 	if err != nil {
@@ -369,3 +545,38 @@ func (s *ApplicationsService) Rollback(ctx context.Context, appID, deploymentID
 
 	return deployment, resp, nil
 }
+
+// WaitForState polls an application until it reaches the given state. It
+// stops early with a *StateError when the application fails instead. After
+// Restart or Scale the application may still report its previous state for
+// a moment, so OnTransition can be used to observe intermediate states.
+func (s *ApplicationsService) WaitForState(ctx context.Context, id string, state ApplicationState, opts *WaitOptions[*Application]) (*Application, error) {
+	ctx, span := s.client.startSpan(ctx, "Applications.WaitForState", Attribute{Key: attrApplicationID, Value: id})
+	defer span.End()
+
+	w := &Waiter[*Application]{
+		Fetch: func(ctx context.Context) (*Application, error) {
+			app, _, err := s.Get(ctx, id)
+			return app, err
+		},
+		State: func(app *Application) string {
+			return string(app.State)
+		},
+		Done: func(app *Application) (bool, error) {
+			if app.State == state {
+				return true, nil
+			}
+			if app.State == StateFailed {
+				return true, &StateError{Resource: "application", ID: app.ID, State: string(app.State)}
+			}
+			return false, nil
+		},
+	}
+
+	result, err := w.Wait(ctx, opts)
+	if err != nil {
+		span.RecordError(err)
+	}
+
+	return result, err
+}
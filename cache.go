@@ -0,0 +1,220 @@
+package sevalla
+
+import (
+	"container/list"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// DefaultCacheSize is the number of responses kept by the MemoryCache that
+// WithCache installs when given a nil Cache
+const DefaultCacheSize = 1000
+
+// CacheEntry is a stored GET response and the validators used to revalidate
+// it
+type CacheEntry struct {
+	ETag         string
+	LastModified string
+
+	// Header holds the headers describing the stored body, such as Link.
+	// Headers describing the response itself, such as the rate limit
+	// state and request ID, are taken from the 304 response instead.
+	Header http.Header
+
+	// Body is the raw response body
+	Body []byte
+}
+
+// Cache stores GET responses by URL. Implementations must be safe for
+// concurrent use.
+type Cache interface {
+	Get(key string) (*CacheEntry, bool)
+	Set(key string, entry *CacheEntry)
+	Delete(key string)
+}
+
+// WithCache enables conditional GET requests. Responses carrying an ETag or
+// Last-Modified header are stored in cache, later GET requests for the same
+// URL send If-None-Match and If-Modified-Since, and a 304 Not Modified
+// response is decoded from the stored body with Response.CacheHit set. A nil
+// cache uses a MemoryCache of DefaultCacheSize entries.
+//
+// Entries are keyed by URL only, so a cache must not be shared by clients
+// using different API keys.
+func WithCache(cache Cache) ClientOption {
+	return func(c *Client) {
+		if cache == nil {
+			cache = NewMemoryCache(DefaultCacheSize)
+		}
+		c.cache = cache
+	}
+}
+
+// MemoryCache is an in-memory Cache that evicts the least recently used
+// entry once it holds its maximum number of entries
+type MemoryCache struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+// memoryCacheItem is an element of the MemoryCache recency list
+type memoryCacheItem struct {
+	key   string
+	entry *CacheEntry
+}
+
+// NewMemoryCache creates a MemoryCache holding up to size entries. A size
+// of zero or less uses DefaultCacheSize.
+func NewMemoryCache(size int) *MemoryCache {
+	if size <= 0 {
+		size = DefaultCacheSize
+	}
+
+	return &MemoryCache{
+		size:    size,
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+// Get returns the entry for key and marks it as recently used
+func (m *MemoryCache) Get(key string) (*CacheEntry, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	elem, ok := m.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	m.order.MoveToFront(elem)
+	return elem.Value.(*memoryCacheItem).entry, true
+}
+
+// Set stores the entry for key, evicting the least recently used entry when
+// the cache is full
+func (m *MemoryCache) Set(key string, entry *CacheEntry) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if elem, ok := m.entries[key]; ok {
+		elem.Value.(*memoryCacheItem).entry = entry
+		m.order.MoveToFront(elem)
+		return
+	}
+
+	m.entries[key] = m.order.PushFront(&memoryCacheItem{key: key, entry: entry})
+
+	if m.order.Len() > m.size {
+		oldest := m.order.Back()
+		m.order.Remove(oldest)
+		delete(m.entries, oldest.Value.(*memoryCacheItem).key)
+	}
+}
+
+// Delete removes the entry for key
+func (m *MemoryCache) Delete(key string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if elem, ok := m.entries[key]; ok {
+		m.order.Remove(elem)
+		delete(m.entries, key)
+	}
+}
+
+// Len returns the number of entries in the cache
+func (m *MemoryCache) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.order.Len()
+}
+
+// cacheable reports whether the response to req may be served from the
+// cache. Requests that set their own validators are left alone.
+func (c *Client) cacheable(req *http.Request, v interface{}) bool {
+	if c.cache == nil || req.Method != http.MethodGet || v == nil {
+		return false
+	}
+
+	if _, ok := v.(io.Writer); ok {
+		return false
+	}
+
+	return req.Header.Get("If-None-Match") == "" && req.Header.Get("If-Modified-Since") == ""
+}
+
+// revalidate returns a copy of req carrying the validators of its cached
+// entry, and the entry. The original headers are left untouched so that
+// retries revalidate against the cache again.
+func (c *Client) revalidate(req *http.Request) (*http.Request, *CacheEntry) {
+	entry, ok := c.cache.Get(req.URL.String())
+	if !ok {
+		return req, nil
+	}
+
+	conditional := new(http.Request)
+	*conditional = *req
+	conditional.Header = req.Header.Clone()
+
+	if entry.ETag != "" {
+		conditional.Header.Set("If-None-Match", entry.ETag)
+	}
+	if entry.LastModified != "" {
+		conditional.Header.Set("If-Modified-Since", entry.LastModified)
+	}
+
+	return conditional, entry
+}
+
+// storeResponse caches a successful response body when it carries
+// validators, and drops the cached entry for resources that are gone
+func (c *Client) storeResponse(req *http.Request, resp *http.Response, body []byte) {
+	key := req.URL.String()
+
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone {
+		c.cache.Delete(key)
+		return
+	}
+
+	etag, lastModified := resp.Header.Get("ETag"), resp.Header.Get("Last-Modified")
+	if resp.StatusCode != http.StatusOK || (etag == "" && lastModified == "") {
+		return
+	}
+
+	header := make(http.Header, len(bodyHeaders))
+	for _, key := range bodyHeaders {
+		if values := resp.Header.Values(key); len(values) > 0 {
+			header[http.CanonicalHeaderKey(key)] = append([]string(nil), values...)
+		}
+	}
+
+	c.cache.Set(key, &CacheEntry{
+		ETag:         etag,
+		LastModified: lastModified,
+		Header:       header,
+		Body:         body,
+	})
+}
+
+// bodyHeaders are the headers describing a response body, restored from
+// the cache on a 304
+var bodyHeaders = []string{"Content-Type", "ETag", "Last-Modified", "Link"}
+
+// notModified completes a 304 response with the headers describing the
+// cached body, unless the 304 sent them, and returns the cached body
+func notModified(resp *http.Response, entry *CacheEntry) []byte {
+	for _, key := range bodyHeaders {
+		if resp.Header.Get(key) == "" {
+			if values := entry.Header.Values(key); len(values) > 0 {
+				resp.Header[http.CanonicalHeaderKey(key)] = append([]string(nil), values...)
+			}
+		}
+	}
+
+	return entry.Body
+}
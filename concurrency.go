@@ -0,0 +1,119 @@
+package sevalla
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// maxModifyAttempts is the number of times Modify reads and updates a
+// resource before returning the precondition failure
+const maxModifyAttempts = 5
+
+// RequestIfMatch makes an update conditional on the resource still having
+// the given ETag, as returned in Response.ETag. The API answers 412
+// Precondition Failed, returned as a *PreconditionFailedError, when the
+// resource has changed since.
+func RequestIfMatch(etag string) RequestOption {
+	return RequestHeader("If-Match", etag)
+}
+
+// RequestIfUnmodifiedSince makes an update conditional on the resource not
+// having been modified after t, typically its UpdatedAt. The API answers
+// 412 Precondition Failed, returned as a *PreconditionFailedError, when the
+// resource has changed since.
+func RequestIfUnmodifiedSince(t time.Time) RequestOption {
+	return RequestHeader("If-Unmodified-Since", t.UTC().Format(http.TimeFormat))
+}
+
+// PreconditionFailedError is returned for 412 Precondition Failed responses,
+// when a resource was modified after the version an update was based on. It
+// matches both ErrPreconditionFailed and ErrConflict.
+type PreconditionFailedError struct {
+	*ErrorResponse
+
+	// IfMatch and IfUnmodifiedSince are the preconditions sent with the
+	// request
+	IfMatch           string
+	IfUnmodifiedSince string
+}
+
+// Error returns the precondition failed error message
+func (e *PreconditionFailedError) Error() string {
+	return fmt.Sprintf("sevalla: resource was modified concurrently (%d) - %s", e.Response.StatusCode, e.Message)
+}
+
+// Is reports whether target is ErrConflict, or a sentinel error matching
+// the underlying ErrorResponse
+func (e *PreconditionFailedError) Is(target error) bool {
+	return target == ErrConflict || e.ErrorResponse.Is(target)
+}
+
+// Unwrap returns the underlying ErrorResponse
+func (e *PreconditionFailedError) Unwrap() error {
+	return e.ErrorResponse
+}
+
+// IsPreconditionFailed returns true if the error is a 412 Precondition
+// Failed, i.e. a conditional update lost a race with another change
+func IsPreconditionFailed(err error) bool {
+	return errors.Is(err, ErrPreconditionFailed)
+}
+
+// newPreconditionFailedError wraps a 412 response with the preconditions
+// of the request that caused it
+func newPreconditionFailedError(e *ErrorResponse) *PreconditionFailedError {
+	err := &PreconditionFailedError{ErrorResponse: e}
+	if req := e.Response.Request; req != nil {
+		err.IfMatch = req.Header.Get("If-Match")
+		err.IfUnmodifiedSince = req.Header.Get("If-Unmodified-Since")
+	}
+	return err
+}
+
+// preconditions returns the request options making an update conditional
+// on the version of a resource that was read, preferring its ETag
+func preconditions(resp *Response, updatedAt time.Time) []RequestOption {
+	switch {
+	case resp != nil && resp.ETag != "":
+		return []RequestOption{RequestIfMatch(resp.ETag)}
+	case !updatedAt.IsZero():
+		return []RequestOption{RequestIfUnmodifiedSince(updatedAt)}
+	}
+	return nil
+}
+
+// modify implements the read-modify-write loop of the services' Modify
+// methods. The resource is fetched, mutate builds an update request from
+// it, and the update is sent conditionally on the fetched version. On a
+// precondition failure the loop starts over with a fresh copy. A nil update
+// request ends the loop without sending anything.
+func modify[T, R any](
+	ctx context.Context,
+	get func(ctx context.Context) (*T, *Response, error),
+	mutate func(current *T) (*R, error),
+	update func(ctx context.Context, updateReq *R) (*T, *Response, error),
+	updatedAt func(current *T) time.Time,
+) (*T, *Response, error) {
+	for attempt := 1; ; attempt++ {
+		current, resp, err := get(ctx)
+		if err != nil {
+			return nil, resp, err
+		}
+
+		updateReq, err := mutate(current)
+		if err != nil {
+			return nil, resp, err
+		}
+		if updateReq == nil {
+			return current, resp, nil
+		}
+
+		updated, resp, err := update(WithRequestOptions(ctx, preconditions(resp, updatedAt(current))...), updateReq)
+		if !IsPreconditionFailed(err) || attempt == maxModifyAttempts {
+			return updated, resp, err
+		}
+	}
+}
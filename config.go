@@ -0,0 +1,373 @@
+package sevalla
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Environment variables read by LoadConfig
+const (
+	EnvAPIKey     = "SEVALLA_API_KEY"
+	EnvBaseURL    = "SEVALLA_BASE_URL"
+	EnvTimeout    = "SEVALLA_TIMEOUT"
+	EnvCompanyID  = "SEVALLA_COMPANY_ID"
+	EnvProfile    = "SEVALLA_PROFILE"
+	EnvConfigFile = "SEVALLA_CONFIG_FILE"
+)
+
+// DefaultProfile is the profile used when none is selected
+const DefaultProfile = "default"
+
+// ErrMissingAPIKey is returned by LoadConfig when no API key is configured
+var ErrMissingAPIKey = errors.New("sevalla: no API key configured")
+
+// Config holds the client settings loaded by LoadConfig
+type Config struct {
+	// Profile is the name of the profile the settings were read from
+	Profile string
+
+	// File is the profile file that was read, empty when there was none
+	File string
+
+	APIKey    string
+	BaseURL   string
+	Timeout   time.Duration
+	CompanyID string
+}
+
+// ConfigOption configures LoadConfig
+type ConfigOption func(*configOptions)
+
+// configOptions holds the settings of a LoadConfig call
+type configOptions struct {
+	file    string
+	profile string
+}
+
+// ConfigFile reads profiles from the given file instead of the one named by
+// SEVALLA_CONFIG_FILE or the default location
+func ConfigFile(path string) ConfigOption {
+	return func(o *configOptions) {
+		o.file = path
+	}
+}
+
+// ConfigProfile selects a profile, taking precedence over SEVALLA_PROFILE
+// and the file's default_profile. The settings of the profile also take
+// precedence over their environment variables. An empty name leaves the
+// selection to SEVALLA_PROFILE and the file.
+func ConfigProfile(name string) ConfigOption {
+	return func(o *configOptions) {
+		if name != "" {
+			o.profile = name
+		}
+	}
+}
+
+// DefaultConfigFile returns the default location of the profile file,
+// config.toml in the sevalla directory of the user's configuration
+// directory, e.g. ~/.config/sevalla/config.toml on Linux
+func DefaultConfigFile() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "sevalla", "config.toml"), nil
+}
+
+// LoadConfig loads client settings from the environment and a profile file.
+//
+// The profile file is the one given with ConfigFile, else the one named by
+// SEVALLA_CONFIG_FILE, else DefaultConfigFile. A missing default file is
+// ignored, a missing file that was asked for is an error. The file holds
+// one table per profile, with top-level keys shared by all profiles:
+//
+//	default_profile = "staging"
+//	timeout = "30s"
+//
+//	[staging]
+//	api_key = "..."
+//	base_url = "https://staging.example.com/v2"
+//
+//	[production]
+//	api_key = "..."
+//	company_id = "company-123"
+//
+// The profile is the one given with ConfigProfile, else SEVALLA_PROFILE,
+// else the file's default_profile, else "default". Selecting a profile that
+// is not in the file is an error, unless it is the "default" profile.
+//
+// Each setting is taken from the first of: its environment variable
+// (SEVALLA_API_KEY, SEVALLA_BASE_URL, SEVALLA_TIMEOUT, SEVALLA_COMPANY_ID),
+// the selected profile, the top-level keys of the file, and the client
+// defaults. A profile given with ConfigProfile comes before the environment
+// variables instead, so a caller selecting it gets its key and base URL.
+// LoadConfig returns an error wrapping ErrMissingAPIKey when no
+// API key is found.
+func LoadConfig(opts ...ConfigOption) (*Config, error) {
+	o := &configOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	cfg := &Config{}
+
+	file, required := o.file, o.file != ""
+	if file == "" {
+		file, required = os.Getenv(EnvConfigFile), os.Getenv(EnvConfigFile) != ""
+	}
+	if file == "" {
+		// Without a user configuration directory there is no default file
+		file, _ = DefaultConfigFile()
+	}
+
+	var profiles map[string]map[string]string
+	if file != "" {
+		var err error
+		profiles, err = readConfigFile(file)
+		switch {
+		case errors.Is(err, os.ErrNotExist) && !required:
+			profiles = nil
+		case err != nil:
+			return nil, err
+		default:
+			cfg.File = file
+		}
+	}
+
+	shared := profiles[""]
+
+	cfg.Profile = o.profile
+	if cfg.Profile == "" {
+		cfg.Profile = os.Getenv(EnvProfile)
+	}
+	if cfg.Profile == "" {
+		cfg.Profile = shared["default_profile"]
+	}
+	if cfg.Profile == "" {
+		cfg.Profile = DefaultProfile
+	}
+
+	profile, ok := profiles[cfg.Profile]
+	if !ok && cfg.Profile != DefaultProfile {
+		if cfg.File == "" {
+			return nil, fmt.Errorf("sevalla: profile %q selected but no profile file found", cfg.Profile)
+		}
+		return nil, fmt.Errorf("sevalla: profile %q not found in %s", cfg.Profile, cfg.File)
+	}
+
+	// A profile selected by the caller is not overridden by the environment
+	explicit := o.profile != ""
+
+	setting := func(env, key string) string {
+		if v, ok := profile[key]; ok && explicit {
+			return v
+		}
+		if v := os.Getenv(env); v != "" {
+			return v
+		}
+		if v, ok := profile[key]; ok {
+			return v
+		}
+		return shared[key]
+	}
+
+	cfg.APIKey = setting(EnvAPIKey, "api_key")
+	cfg.BaseURL = setting(EnvBaseURL, "base_url")
+	cfg.CompanyID = setting(EnvCompanyID, "company_id")
+
+	if timeout := setting(EnvTimeout, "timeout"); timeout != "" {
+		d, err := parseTimeout(timeout)
+		if err != nil {
+			return nil, fmt.Errorf("sevalla: invalid timeout %q: %w", timeout, err)
+		}
+		cfg.Timeout = d
+	}
+
+	if cfg.APIKey == "" {
+		return nil, fmt.Errorf("%w: set %s or api_key in profile %q", ErrMissingAPIKey, EnvAPIKey, cfg.Profile)
+	}
+
+	return cfg, nil
+}
+
+// Options returns the client options applying the configuration
+func (cfg *Config) Options() []ClientOption {
+	opts := []ClientOption{WithAPIKey(cfg.APIKey)}
+	if cfg.BaseURL != "" {
+		opts = append(opts, WithBaseURL(cfg.BaseURL))
+	}
+	if cfg.Timeout > 0 {
+		opts = append(opts, WithTimeout(cfg.Timeout))
+	}
+	if cfg.CompanyID != "" {
+		opts = append(opts, WithCompanyID(cfg.CompanyID))
+	}
+	return opts
+}
+
+// NewClientFromEnv creates a client from the settings found by LoadConfig
+// with no options, i.e. from the SEVALLA_* environment variables and the
+// selected profile of the profile file. The given options are applied after
+// the loaded ones.
+func NewClientFromEnv(opts ...ClientOption) (*Client, error) {
+	cfg, err := LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewClient(append(cfg.Options(), opts...)...), nil
+}
+
+// parseTimeout parses a duration such as "30s", or a number of seconds
+func parseTimeout(s string) (time.Duration, error) {
+	if seconds, err := strconv.Atoi(s); err == nil {
+		return time.Duration(seconds) * time.Second, nil
+	}
+	return time.ParseDuration(s)
+}
+
+// configKeys are the keys allowed in the profile file
+var configKeys = map[string]bool{
+	"api_key":    true,
+	"base_url":   true,
+	"timeout":    true,
+	"company_id": true,
+}
+
+// readConfigFile reads a profile file. Top-level keys are returned under
+// the empty profile name.
+func readConfigFile(path string) (map[string]map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	profiles, err := parseConfig(f)
+	if err != nil {
+		return nil, fmt.Errorf("sevalla: %s: %w", path, err)
+	}
+
+	return profiles, nil
+}
+
+// parseConfig parses the subset of TOML used by profile files: tables,
+// comments, and keys holding strings, integers or booleans
+func parseConfig(r io.Reader) (map[string]map[string]string, error) {
+	profiles := map[string]map[string]string{"": {}}
+	current := ""
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(stripComment(scanner.Text()))
+		if text == "" {
+			continue
+		}
+
+		if strings.HasPrefix(text, "[") {
+			if !strings.HasSuffix(text, "]") {
+				return nil, fmt.Errorf("line %d: malformed table header", line)
+			}
+
+			name, err := configString(strings.TrimSpace(text[1 : len(text)-1]))
+			if err != nil || name == "" {
+				return nil, fmt.Errorf("line %d: invalid profile name", line)
+			}
+			if _, ok := profiles[name]; ok {
+				return nil, fmt.Errorf("line %d: duplicate profile %q", line, name)
+			}
+
+			profiles[name] = map[string]string{}
+			current = name
+			continue
+		}
+
+		key, value, ok := strings.Cut(text, "=")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected key = value", line)
+		}
+
+		key = strings.TrimSpace(key)
+		if !configKeys[key] && (key != "default_profile" || current != "") {
+			return nil, fmt.Errorf("line %d: unknown key %q", line, key)
+		}
+
+		value, err := configValue(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %s: %w", line, key, err)
+		}
+
+		profiles[current][key] = value
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return profiles, nil
+}
+
+// stripComment removes a trailing comment outside of quoted strings
+func stripComment(line string) string {
+	var quote rune
+	escaped := false
+	for i, r := range line {
+		switch {
+		case escaped:
+			escaped = false
+		case quote == '"' && r == '\\':
+			escaped = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '#':
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// configValue converts a TOML string, integer or boolean to its string form
+func configValue(s string) (string, error) {
+	switch {
+	case strings.HasPrefix(s, `"`) || strings.HasPrefix(s, "'"):
+		return configString(s)
+	case s == "true" || s == "false":
+		return s, nil
+	}
+
+	if _, err := strconv.ParseInt(strings.ReplaceAll(s, "_", ""), 10, 64); err != nil {
+		return "", fmt.Errorf("unsupported value %s", s)
+	}
+	return strings.ReplaceAll(s, "_", ""), nil
+}
+
+// configString unquotes a basic or literal TOML string. Bare words, as used
+// for table names, are returned as they are.
+func configString(s string) (string, error) {
+	switch {
+	case strings.HasPrefix(s, `"`):
+		return strconv.Unquote(s)
+	case strings.HasPrefix(s, "'"):
+		if len(s) < 2 || !strings.HasSuffix(s, "'") {
+			return "", errors.New("unterminated string")
+		}
+		return s[1 : len(s)-1], nil
+	case strings.ContainsAny(s, " \t\"'="):
+		return "", fmt.Errorf("unquoted value %s", s)
+	}
+	return s, nil
+}
@@ -0,0 +1,227 @@
+package sevalla
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CredentialsProvider supplies the API key of a client. It is consulted for
+// every request, so keys can be rotated without rebuilding the client.
+// Implementations must be safe for concurrent use.
+type CredentialsProvider interface {
+	// APIKey returns the API key to authenticate a request with
+	APIKey(ctx context.Context) (string, error)
+
+	// Invalidate discards any cached key. The client calls it when the API
+	// rejects a key with 401 Unauthorized, then asks for the key again and
+	// retries the request once if it changed.
+	Invalidate()
+}
+
+// WithCredentials authenticates requests with the keys supplied by the
+// given provider
+func WithCredentials(provider CredentialsProvider) ClientOption {
+	return func(c *Client) {
+		c.credentials = provider
+	}
+}
+
+// StaticCredentials is a CredentialsProvider returning a fixed API key. It
+// is what WithAPIKey installs.
+type StaticCredentials string
+
+// APIKey returns the key
+func (s StaticCredentials) APIKey(context.Context) (string, error) {
+	return string(s), nil
+}
+
+// Invalidate does nothing, a static key cannot be refreshed
+func (StaticCredentials) Invalidate() {}
+
+// EnvCredentials is a CredentialsProvider reading the API key from the named
+// environment variable on every request
+type EnvCredentials string
+
+// APIKey returns the value of the environment variable
+func (e EnvCredentials) APIKey(context.Context) (string, error) {
+	key := os.Getenv(string(e))
+	if key == "" {
+		return "", fmt.Errorf("%w: %s is not set", ErrMissingAPIKey, string(e))
+	}
+	return key, nil
+}
+
+// Invalidate does nothing, the variable is read on every request
+func (EnvCredentials) Invalidate() {}
+
+// FileCredentials is a CredentialsProvider reading the API key from a file,
+// such as a mounted Kubernetes secret. The file is read again whenever its
+// modification time or size changes.
+type FileCredentials struct {
+	path string
+
+	mu      sync.Mutex
+	key     string
+	modTime time.Time
+	size    int64
+}
+
+// NewFileCredentials creates a FileCredentials reading the given file.
+// Surrounding whitespace in the file is ignored.
+func NewFileCredentials(path string) *FileCredentials {
+	return &FileCredentials{path: path}
+}
+
+// APIKey returns the key stored in the file, reading it if it changed
+func (f *FileCredentials) APIKey(context.Context) (string, error) {
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return "", fmt.Errorf("sevalla: reading credentials: %w", err)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.key != "" && info.ModTime().Equal(f.modTime) && info.Size() == f.size {
+		return f.key, nil
+	}
+
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		return "", fmt.Errorf("sevalla: reading credentials: %w", err)
+	}
+
+	key := strings.TrimSpace(string(data))
+	if key == "" {
+		return "", fmt.Errorf("%w: %s is empty", ErrMissingAPIKey, f.path)
+	}
+
+	f.key, f.modTime, f.size = key, info.ModTime(), info.Size()
+	return f.key, nil
+}
+
+// Invalidate makes the next call read the file again
+func (f *FileCredentials) Invalidate() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.key = ""
+}
+
+// CommandCredentials is a CredentialsProvider running an external command,
+// such as a secret manager CLI, and using its trimmed standard output as the
+// API key. The key is cached for the configured TTL, or until invalidated
+// when the TTL is zero.
+type CommandCredentials struct {
+	name string
+	args []string
+	ttl  time.Duration
+
+	mu        sync.Mutex
+	key       string
+	fetchedAt time.Time
+}
+
+// NewCommandCredentials creates a CommandCredentials running the named
+// command with the given arguments
+func NewCommandCredentials(ttl time.Duration, name string, args ...string) *CommandCredentials {
+	return &CommandCredentials{name: name, args: args, ttl: ttl}
+}
+
+// APIKey returns the cached key, running the command when there is none or
+// it has expired
+func (c *CommandCredentials) APIKey(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.key != "" && (c.ttl == 0 || time.Since(c.fetchedAt) < c.ttl) {
+		return c.key, nil
+	}
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, c.name, c.args...)
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("sevalla: running credentials command %s: %w: %s", c.name, err, msg)
+		}
+		return "", fmt.Errorf("sevalla: running credentials command %s: %w", c.name, err)
+	}
+
+	key := strings.TrimSpace(string(out))
+	if key == "" {
+		return "", fmt.Errorf("%w: credentials command %s printed nothing", ErrMissingAPIKey, c.name)
+	}
+
+	c.key, c.fetchedAt = key, time.Now()
+	return c.key, nil
+}
+
+// Invalidate makes the next call run the command again
+func (c *CommandCredentials) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.key = ""
+}
+
+// authenticate sets the Authorization header of req from the client's
+// credentials provider
+func (c *Client) authenticate(ctx context.Context, req *http.Request) error {
+	if c.credentials == nil {
+		return nil
+	}
+
+	key, err := c.credentials.APIKey(ctx)
+	if err != nil {
+		return err
+	}
+
+	if key != "" {
+		req.Header.Set("Authorization", "Bearer "+key)
+	}
+
+	return nil
+}
+
+// credentialsMiddleware retries a request once with fresh credentials when
+// the API rejects its key. It is the outermost middleware, so the retried
+// request passes through the whole chain again.
+func credentialsMiddleware(provider CredentialsProvider) Middleware {
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request, v interface{}) (*Response, error) {
+			response, err := next.Do(req, v)
+			if !errors.Is(err, ErrUnauthorized) {
+				return response, err
+			}
+
+			provider.Invalidate()
+
+			key, keyErr := provider.APIKey(req.Context())
+			if keyErr != nil || key == "" || req.Header.Get("Authorization") == "Bearer "+key {
+				return response, err
+			}
+
+			if rewindErr := rewindBody(req); rewindErr != nil {
+				return response, err
+			}
+
+			retry := new(http.Request)
+			*retry = *req
+			retry.Header = req.Header.Clone()
+			retry.Header.Set("Authorization", "Bearer "+key)
+
+			return next.Do(retry, v)
+		})
+	}
+}
@@ -3,6 +3,9 @@ package sevalla
 import (
 	"context"
 	"fmt"
+	"iter"
+	"strings"
+	"time"
 )
 
 // DatabasesService handles communication with the database-related
@@ -43,8 +46,53 @@ type RestoreBackupRequest struct {
 	BackupID string `json:"backup_id"`
 }
 
+// minStorageGB is the smallest storage size of a database
+const minStorageGB = 1
+
+// Validate checks the request for errors the API would reject
+func (r *CreateDatabaseRequest) Validate() error {
+	if r == nil {
+		return nilRequest()
+	}
+
+	var v validator
+	v.required("Name", "name", r.Name)
+	switch {
+	case r.Type == "":
+		v.add("Type", "type", "required", "is required")
+	case !r.Type.IsValid():
+		v.add("Type", "type", "invalid", fmt.Sprintf("unknown engine %q", r.Type))
+	case r.Version != "" && !r.Type.SupportsVersion(r.Version):
+		v.add("Version", "version", "invalid", fmt.Sprintf("%s %s is not available, use one of %s",
+			r.Type, r.Version, strings.Join(r.Type.Versions(), ", ")))
+	}
+	v.region("Region", "location", r.Region)
+	if r.Storage != 0 {
+		v.min("Storage", "storage_gb", r.Storage, minStorageGB)
+	}
+	return v.err()
+}
+
+// Validate checks the request for errors the API would reject
+func (r *UpdateDatabaseRequest) Validate() error {
+	if r == nil {
+		return nilRequest()
+	}
+
+	var v validator
+	v.notEmpty("Name", "name", r.Name)
+	v.notEmpty("Size", "size", r.Size)
+	if r.Storage != nil {
+		v.min("Storage", "storage_gb", *r.Storage, minStorageGB)
+	}
+	return v.err()
+}
+
 // List returns all databases
 func (s *DatabasesService) List(ctx context.Context, opts *ListOptions) ([]*Database, *Response, error) {
+	ctx, span := s.client.startSpan(ctx, "Databases.List")
+	defer span.End()
+
 	u := "databases"
 	req, err := s.client.NewRequestWithQuery(ctx, "GET", u, opts)
 	if err != nil {
@@ -60,8 +108,21 @@ func (s *DatabasesService) List(ctx context.Context, opts *ListOptions) ([]*Data
 	return databases, resp, nil
 }
 
+// ListIter returns an iterator over all databases across all pages
+func (s *DatabasesService) ListIter(ctx context.Context, opts *ListOptions) iter.Seq2[*Database, error] {
+	return paginate(ctx, s.client, "Databases.ListIter", opts, s.List)
+}
+
+// ListAll returns all databases across all pages
+func (s *DatabasesService) ListAll(ctx context.Context, opts *ListOptions) ([]*Database, error) {
+	return collect(paginate(ctx, s.client, "Databases.ListAll", opts, s.List))
+}
+
 // Get returns a single database by ID
 func (s *DatabasesService) Get(ctx context.Context, id string) (*Database, *Response, error) {
+	ctx, span := s.client.startSpan(ctx, "Databases.Get", Attribute{Key: attrDatabaseID, Value: id})
+	defer span.End()
+
 	u := fmt.Sprintf("databases/%s", id)
 	req, err := s.client.NewRequest(ctx, "GET", u, nil)
 	if err != nil {
@@ -79,6 +140,9 @@ func (s *DatabasesService) Get(ctx context.Context, id string) (*Database, *Resp
 
 // Create creates a new database
 func (s *DatabasesService) Create(ctx context.Context, createReq *CreateDatabaseRequest) (*Database, *Response, error) {
+	ctx, span := s.client.startSpan(ctx, "Databases.Create")
+	defer span.End()
+
 	u := "databases"
 	req, err := s.client.NewRequest(ctx, "POST", u, createReq)
 	if err != nil {
@@ -96,6 +160,9 @@ func (s *DatabasesService) Create(ctx context.Context, createReq *CreateDatabase
 
 // Update updates an existing database
 func (s *DatabasesService) Update(ctx context.Context, id string, updateReq *UpdateDatabaseRequest) (*Database, *Response, error) {
+	ctx, span := s.client.startSpan(ctx, "Databases.Update", Attribute{Key: attrDatabaseID, Value: id})
+	defer span.End()
+
 	u := fmt.Sprintf("databases/%s", id)
 	req, err := s.client.NewRequest(ctx, "PATCH", u, updateReq)
 	if err != nil {
@@ -111,8 +178,36 @@ func (s *DatabasesService) Update(ctx context.Context, id string, updateReq *Upd
 	return database, resp, nil
 }
 
+// Modify applies a read-modify-write change to a database. It fetches the
+// database, passes it to mutate to build the update, and sends the update
+// conditionally on the fetched version. When another change lands in
+// between, the database is fetched again and mutate called with the new
+// version, up to 5 times. A nil update request from mutate leaves the
+// database untouched.
+func (s *DatabasesService) Modify(ctx context.Context, id string, mutate func(database *Database) (*UpdateDatabaseRequest, error)) (*Database, *Response, error) {
+	ctx, span := s.client.startSpan(ctx, "Databases.Modify", Attribute{Key: attrDatabaseID, Value: id})
+	defer span.End()
+
+	database, resp, err := modify(ctx,
+		func(ctx context.Context) (*Database, *Response, error) { return s.Get(ctx, id) },
+		mutate,
+		func(ctx context.Context, updateReq *UpdateDatabaseRequest) (*Database, *Response, error) {
+			return s.Update(ctx, id, updateReq)
+		},
+		func(database *Database) time.Time { return database.UpdatedAt },
+	)
+	if err != nil {
+		span.RecordError(err)
+	}
+
+	return database, resp, err
+}
+
 // Delete deletes a database
 func (s *DatabasesService) Delete(ctx context.Context, id string) (*Response, error) {
+	ctx, span := s.client.startSpan(ctx, "Databases.Delete", Attribute{Key: attrDatabaseID, Value: id})
+	defer span.End()
+
 	u := fmt.Sprintf("databases/%s", id)
 	req, err := s.client.NewRequest(ctx, "DELETE", u, nil)
 	if err != nil {
@@ -124,6 +219,9 @@ func (s *DatabasesService) Delete(ctx context.Context, id string) (*Response, er
 
 // GetCredentials retrieves database connection credentials
 func (s *DatabasesService) GetCredentials(ctx context.Context, id string) (*Database, *Response, error) {
+	ctx, span := s.client.startSpan(ctx, "Databases.GetCredentials", Attribute{Key: attrDatabaseID, Value: id})
+	defer span.End()
+
 	u := fmt.Sprintf("databases/%s/credentials", id)
 	req, err := s.client.NewRequest(ctx, "GET", u, nil)
 	if err != nil {
@@ -141,6 +239,9 @@ func (s *DatabasesService) GetCredentials(ctx context.Context, id string) (*Data
 
 // ResetPassword resets the database password
 func (s *DatabasesService) ResetPassword(ctx context.Context, id string) (*Database, *Response, error) {
+	ctx, span := s.client.startSpan(ctx, "Databases.ResetPassword", Attribute{Key: attrDatabaseID, Value: id})
+	defer span.End()
+
 	u := fmt.Sprintf("databases/%s/reset-password", id)
 	req, err := s.client.NewRequest(ctx, "POST", u, nil)
 	if err != nil {
@@ -158,6 +259,9 @@ func (s *DatabasesService) ResetPassword(ctx context.Context, id string) (*Datab
 
 // ListBackups lists all backups for a database
 func (s *DatabasesService) ListBackups(ctx context.Context, id string, opts *ListOptions) ([]*Backup, *Response, error) {
+	ctx, span := s.client.startSpan(ctx, "Databases.ListBackups", Attribute{Key: attrDatabaseID, Value: id})
+	defer span.End()
+
 	u := fmt.Sprintf("databases/%s/backups", id)
 	req, err := s.client.NewRequestWithQuery(ctx, "GET", u, opts)
 	if err != nil {
@@ -173,8 +277,25 @@ func (s *DatabasesService) ListBackups(ctx context.Context, id string, opts *Lis
 	return backups, resp, nil
 }
 
+// ListBackupsIter returns an iterator over all backups for a database across all pages
+func (s *DatabasesService) ListBackupsIter(ctx context.Context, id string, opts *ListOptions) iter.Seq2[*Backup, error] {
+	return paginate(ctx, s.client, "Databases.ListBackupsIter", opts, func(ctx context.Context, opts *ListOptions) ([]*Backup, *Response, error) {
+		return s.ListBackups(ctx, id, opts)
+	}, Attribute{Key: attrDatabaseID, Value: id})
+}
+
+// ListAllBackups returns all backups for a database across all pages
+func (s *DatabasesService) ListAllBackups(ctx context.Context, id string, opts *ListOptions) ([]*Backup, error) {
+	return collect(paginate(ctx, s.client, "Databases.ListAllBackups", opts, func(ctx context.Context, opts *ListOptions) ([]*Backup, *Response, error) {
+		return s.ListBackups(ctx, id, opts)
+	}, Attribute{Key: attrDatabaseID, Value: id}))
+}
+
 // CreateBackup creates a new backup for a database
 func (s *DatabasesService) CreateBackup(ctx context.Context, id string, backupReq *CreateBackupRequest) (*Backup, *Response, error) {
+	ctx, span := s.client.startSpan(ctx, "Databases.CreateBackup", Attribute{Key: attrDatabaseID, Value: id})
+	defer span.End()
+
 	u := fmt.Sprintf("databases/%s/backups", id)
 	req, err := s.client.NewRequest(ctx, "POST", u, backupReq)
 	if err != nil {
@@ -192,6 +313,9 @@ func (s *DatabasesService) CreateBackup(ctx context.Context, id string, backupRe
 
 // GetBackup gets a specific backup
 func (s *DatabasesService) GetBackup(ctx context.Context, dbID, backupID string) (*Backup, *Response, error) {
+	ctx, span := s.client.startSpan(ctx, "Databases.GetBackup", Attribute{Key: attrDatabaseID, Value: dbID}, Attribute{Key: attrBackupID, Value: backupID})
+	defer span.End()
+
 	u := fmt.Sprintf("databases/%s/backups/%s", dbID, backupID)
 	req, err := s.client.NewRequest(ctx, "GET", u, nil)
 	if err != nil {
@@ -209,6 +333,9 @@ func (s *DatabasesService) GetBackup(ctx context.Context, dbID, backupID string)
 
 // DeleteBackup deletes a backup
 func (s *DatabasesService) DeleteBackup(ctx context.Context, dbID, backupID string) (*Response, error) {
+	ctx, span := s.client.startSpan(ctx, "Databases.DeleteBackup", Attribute{Key: attrDatabaseID, Value: dbID}, Attribute{Key: attrBackupID, Value: backupID})
+	defer span.End()
+
 	u := fmt.Sprintf("databases/%s/backups/%s", dbID, backupID)
 	req, err := s.client.NewRequest(ctx, "DELETE", u, nil)
 	if err != nil {
@@ -220,6 +347,9 @@ func (s *DatabasesService) DeleteBackup(ctx context.Context, dbID, backupID stri
 
 // RestoreFromBackup restores a database from a backup
 func (s *DatabasesService) RestoreFromBackup(ctx context.Context, id string, restoreReq *RestoreBackupRequest) (*Response, error) {
+	ctx, span := s.client.startSpan(ctx, "Databases.RestoreFromBackup", Attribute{Key: attrDatabaseID, Value: id})
+	defer span.End()
+
 	u := fmt.Sprintf("databases/%s/restore", id)
 	req, err := s.client.NewRequest(ctx, "POST", u, restoreReq)
 	if err != nil {
@@ -231,6 +361,9 @@ func (s *DatabasesService) RestoreFromBackup(ctx context.Context, id string, res
 
 // GetUsage retrieves usage metrics for a database
 func (s *DatabasesService) GetUsage(ctx context.Context, id string, period string) (*Usage, *Response, error) {
+	ctx, span := s.client.startSpan(ctx, "Databases.GetUsage", Attribute{Key: attrDatabaseID, Value: id})
+	defer span.End()
+
 	u := fmt.Sprintf("databases/%s/usage", id)
 	if period != "" {
 		u = fmt.Sprintf("%s?period=%s", u, period)
@@ -252,6 +385,9 @@ func (s *DatabasesService) GetUsage(ctx context.Context, id string, period strin
 
 // EnablePublicAccess enables public access to a database
 func (s *DatabasesService) EnablePublicAccess(ctx context.Context, id string) (*Database, *Response, error) {
+	ctx, span := s.client.startSpan(ctx, "Databases.EnablePublicAccess", Attribute{Key: attrDatabaseID, Value: id})
+	defer span.End()
+
 	u := fmt.Sprintf("databases/%s/public-access", id)
 	req, err := s.client.NewRequest(ctx, "PUT", u, map[string]bool{"enabled": true})
 	if err != nil {
@@ -269,6 +405,9 @@ func (s *DatabasesService) EnablePublicAccess(ctx context.Context, id string) (*
 
 // DisablePublicAccess disables public access to a database
 func (s *DatabasesService) DisablePublicAccess(ctx context.Context, id string) (*Database, *Response, error) {
+	ctx, span := s.client.startSpan(ctx, "Databases.DisablePublicAccess", Attribute{Key: attrDatabaseID, Value: id})
+	defer span.End()
+
 	u := fmt.Sprintf("databases/%s/public-access", id)
 	req, err := s.client.NewRequest(ctx, "PUT", u, map[string]bool{"enabled": false})
 	if err != nil {
@@ -283,3 +422,66 @@ func (s *DatabasesService) DisablePublicAccess(ctx context.Context, id string) (
 
 	return database, resp, nil
 }
+
+// WaitUntilReady polls a database until it is provisioned and reachable
+// through its internal URL
+func (s *DatabasesService) WaitUntilReady(ctx context.Context, id string, opts *WaitOptions[*Database]) (*Database, error) {
+	ctx, span := s.client.startSpan(ctx, "Databases.WaitUntilReady", Attribute{Key: attrDatabaseID, Value: id})
+	defer span.End()
+
+	w := &Waiter[*Database]{
+		Fetch: func(ctx context.Context) (*Database, error) {
+			database, _, err := s.Get(ctx, id)
+			return database, err
+		},
+		State: func(database *Database) string {
+			if database.InternalURL != "" {
+				return "ready"
+			}
+			return "provisioning"
+		},
+		Done: func(database *Database) (bool, error) {
+			return database.InternalURL != "", nil
+		},
+	}
+
+	result, err := w.Wait(ctx, opts)
+	if err != nil {
+		span.RecordError(err)
+	}
+
+	return result, err
+}
+
+// WaitForBackup polls a backup until it completes. It stops early with a
+// *StateError when the backup fails.
+func (s *DatabasesService) WaitForBackup(ctx context.Context, dbID, backupID string, opts *WaitOptions[*Backup]) (*Backup, error) {
+	ctx, span := s.client.startSpan(ctx, "Databases.WaitForBackup", Attribute{Key: attrDatabaseID, Value: dbID}, Attribute{Key: attrBackupID, Value: backupID})
+	defer span.End()
+
+	w := &Waiter[*Backup]{
+		Fetch: func(ctx context.Context) (*Backup, error) {
+			backup, _, err := s.GetBackup(ctx, dbID, backupID)
+			return backup, err
+		},
+		State: func(backup *Backup) string {
+			return backup.Status
+		},
+		Done: func(backup *Backup) (bool, error) {
+			switch backup.Status {
+			case BackupStatusCompleted:
+				return true, nil
+			case BackupStatusFailed:
+				return true, &StateError{Resource: "backup", ID: backup.ID, State: backup.Status}
+			}
+			return false, nil
+		},
+	}
+
+	result, err := w.Wait(ctx, opts)
+	if err != nil {
+		span.RecordError(err)
+	}
+
+	return result, err
+}
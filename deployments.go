@@ -3,6 +3,7 @@ package sevalla
 import (
 	"context"
 	"fmt"
+	"iter"
 )
 
 // DeploymentsService handles communication with the deployment-related
@@ -13,6 +14,9 @@ type DeploymentsService struct {
 
 // Get returns a single deployment by ID
 func (s *DeploymentsService) Get(ctx context.Context, id string) (*Deployment, *Response, error) {
+	ctx, span := s.client.startSpan(ctx, "Deployments.Get", Attribute{Key: attrDeploymentID, Value: id})
+	defer span.End()
+
 	u := fmt.Sprintf("deployments/%s", id)
 	req, err := s.client.NewRequest(ctx, "GET", u, nil)
 	if err != nil {
@@ -30,6 +34,9 @@ func (s *DeploymentsService) Get(ctx context.Context, id string) (*Deployment, *
 
 // List returns all deployments
 func (s *DeploymentsService) List(ctx context.Context, opts *ListOptions) ([]*Deployment, *Response, error) {
+	ctx, span := s.client.startSpan(ctx, "Deployments.List")
+	defer span.End()
+
 	u := "deployments"
 	req, err := s.client.NewRequestWithQuery(ctx, "GET", u, opts)
 	if err != nil {
@@ -45,8 +52,21 @@ func (s *DeploymentsService) List(ctx context.Context, opts *ListOptions) ([]*De
 	return deployments, resp, nil
 }
 
+// ListIter returns an iterator over all deployments across all pages
+func (s *DeploymentsService) ListIter(ctx context.Context, opts *ListOptions) iter.Seq2[*Deployment, error] {
+	return paginate(ctx, s.client, "Deployments.ListIter", opts, s.List)
+}
+
+// ListAll returns all deployments across all pages
+func (s *DeploymentsService) ListAll(ctx context.Context, opts *ListOptions) ([]*Deployment, error) {
+	return collect(paginate(ctx, s.client, "Deployments.ListAll", opts, s.List))
+}
+
 // GetLogs retrieves deployment logs
 func (s *DeploymentsService) GetLogs(ctx context.Context, id string) (string, *Response, error) {
+	ctx, span := s.client.startSpan(ctx, "Deployments.GetLogs", Attribute{Key: attrDeploymentID, Value: id})
+	defer span.End()
+
 	u := fmt.Sprintf("deployments/%s/logs", id)
 	req, err := s.client.NewRequest(ctx, "GET", u, nil)
 	if err != nil {
@@ -64,8 +84,18 @@ func (s *DeploymentsService) GetLogs(ctx context.Context, id string) (string, *R
 	return result.Logs, resp, nil
 }
 
+// TailLogs streams deployment logs as they are written, following the
+// build until the deployment finishes
+func (s *DeploymentsService) TailLogs(ctx context.Context, id string, opts *TailLogsOptions) (*LogStream, error) {
+	u := fmt.Sprintf("deployments/%s/logs/stream", id)
+	return s.client.tailLogs(ctx, "Deployments.TailLogs", u, opts, Attribute{Key: attrDeploymentID, Value: id})
+}
+
 // Cancel cancels a deployment
 func (s *DeploymentsService) Cancel(ctx context.Context, id string) (*Response, error) {
+	ctx, span := s.client.startSpan(ctx, "Deployments.Cancel", Attribute{Key: attrDeploymentID, Value: id})
+	defer span.End()
+
 	u := fmt.Sprintf("deployments/%s/cancel", id)
 	req, err := s.client.NewRequest(ctx, "POST", u, nil)
 	if err != nil {
@@ -74,3 +104,37 @@ func (s *DeploymentsService) Cancel(ctx context.Context, id string) (*Response,
 
 	return s.client.Do(req, nil)
 }
+
+// WaitForDeployment polls a deployment until it reaches a terminal status.
+// It returns the final deployment, along with a *DeploymentError when the
+// deployment failed or was cancelled.
+func (s *DeploymentsService) WaitForDeployment(ctx context.Context, id string, opts *WaitOptions[*Deployment]) (*Deployment, error) {
+	ctx, span := s.client.startSpan(ctx, "Deployments.WaitForDeployment", Attribute{Key: attrDeploymentID, Value: id})
+	defer span.End()
+
+	w := &Waiter[*Deployment]{
+		Fetch: func(ctx context.Context) (*Deployment, error) {
+			deployment, _, err := s.Get(ctx, id)
+			return deployment, err
+		},
+		State: func(d *Deployment) string {
+			return string(d.State)
+		},
+		Done: func(d *Deployment) (bool, error) {
+			if !d.State.IsTerminal() {
+				return false, nil
+			}
+			if d.State != StatusSuccess {
+				return true, &DeploymentError{Deployment: d}
+			}
+			return true, nil
+		},
+	}
+
+	result, err := w.Wait(ctx, opts)
+	if err != nil {
+		span.RecordError(err)
+	}
+
+	return result, err
+}
@@ -0,0 +1,164 @@
+package sevalla
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// WithDryRun puts the client in dry-run mode. Requests other than GET and
+// HEAD are recorded in the given plan instead of being sent, and answered
+// with a synthetic 202 Accepted response with Response.DryRun set and an
+// empty body, so services return zero-value resources. GET requests are
+// sent as usual. A nil plan disables dry-run mode.
+//
+//	var plan sevalla.DryRunPlan
+//	client := sevalla.NewClient(sevalla.WithAPIKey(apiKey), sevalla.WithDryRun(&plan))
+//	// ... run the script ...
+//	fmt.Print(&plan)
+func WithDryRun(plan *DryRunPlan) ClientOption {
+	return func(c *Client) {
+		c.dryRun = plan
+	}
+}
+
+// PlannedRequest is a request recorded in dry-run mode
+type PlannedRequest struct {
+	Method string
+	Path   string
+
+	// Body is the JSON request body, nil for requests without one
+	Body json.RawMessage
+}
+
+// DryRunPlan records the requests a client in dry-run mode would have sent.
+// The zero value is an empty plan ready to use. It is safe for concurrent
+// use.
+type DryRunPlan struct {
+	mu       sync.Mutex
+	requests []PlannedRequest
+}
+
+// Requests returns the recorded requests in the order they were made
+func (p *DryRunPlan) Requests() []PlannedRequest {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return append([]PlannedRequest(nil), p.requests...)
+}
+
+// Len returns the number of recorded requests
+func (p *DryRunPlan) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return len(p.requests)
+}
+
+// Reset discards the recorded requests
+func (p *DryRunPlan) Reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.requests = nil
+}
+
+// record appends a request to the plan
+func (p *DryRunPlan) record(r PlannedRequest) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.requests = append(p.requests, r)
+}
+
+// String returns the plan as a diff, one block per request marked with +
+// for POST, ~ for PUT and PATCH and - for DELETE. Bodies are indented, with
+// passwords and environment variable values redacted as in debug logs.
+func (p *DryRunPlan) String() string {
+	requests := p.Requests()
+	if len(requests) == 0 {
+		return "No changes planned.\n"
+	}
+
+	var b strings.Builder
+	for _, r := range requests {
+		marker := planMarker(r.Method)
+		fmt.Fprintf(&b, "%s %s %s\n", marker, r.Method, r.Path)
+
+		if len(r.Body) == 0 {
+			continue
+		}
+
+		var body bytes.Buffer
+		path, _, _ := strings.Cut(r.Path, "?")
+		if err := json.Indent(&body, []byte(redactBody(path, r.Body)), "", "  "); err != nil {
+			body.Reset()
+			body.WriteString(redacted)
+		}
+		for _, line := range strings.Split(body.String(), "\n") {
+			fmt.Fprintf(&b, "%s     %s\n", marker, line)
+		}
+	}
+
+	fmt.Fprintf(&b, "\n%d %s planned, none sent.\n", len(requests), plural(len(requests), "request", "requests"))
+
+	return b.String()
+}
+
+// planMarker returns the diff marker of a request method
+func planMarker(method string) string {
+	switch method {
+	case http.MethodPost:
+		return "+"
+	case http.MethodDelete:
+		return "-"
+	}
+	return "~"
+}
+
+// plural returns singular when n is 1, and plural otherwise
+func plural(n int, singular, plural string) string {
+	if n == 1 {
+		return singular
+	}
+	return plural
+}
+
+// dryRunMiddleware records requests other than GET and HEAD in the plan and
+// answers them without calling the rest of the chain
+func dryRunMiddleware(plan *DryRunPlan) Middleware {
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request, v interface{}) (*Response, error) {
+			if req.Method == http.MethodGet || req.Method == http.MethodHead {
+				return next.Do(req, v)
+			}
+
+			planned := PlannedRequest{Method: req.Method, Path: req.URL.Path}
+			if req.URL.RawQuery != "" {
+				planned.Path += "?" + req.URL.RawQuery
+			}
+			if body := requestBody(req); len(body) > 0 {
+				planned.Body = json.RawMessage(bytes.TrimSpace(body))
+			}
+			plan.record(planned)
+
+			return &Response{
+				Response: &http.Response{
+					Status:     "202 Accepted",
+					StatusCode: http.StatusAccepted,
+					Proto:      "HTTP/1.1",
+					ProtoMajor: 1,
+					ProtoMinor: 1,
+					Header:     http.Header{},
+					Body:       io.NopCloser(strings.NewReader("")),
+					Request:    req,
+				},
+				DryRun: true,
+			}, nil
+		})
+	}
+}
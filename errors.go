@@ -1,8 +1,11 @@
 package sevalla
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"slices"
 )
 
 // ErrorResponse represents an error response from the Sevalla API
@@ -34,82 +37,145 @@ func (e *ErrorResponse) Error() string {
 	return fmt.Sprintf("sevalla: %d - %s", e.Response.StatusCode, e.Message)
 }
 
+// Sentinel errors matched by API errors with errors.Is
+var (
+	ErrBadRequest          = errors.New("sevalla: bad request")
+	ErrUnauthorized        = errors.New("sevalla: unauthorized")
+	ErrForbidden           = errors.New("sevalla: forbidden")
+	ErrNotFound            = errors.New("sevalla: not found")
+	ErrConflict            = errors.New("sevalla: conflict")
+	ErrPreconditionFailed  = errors.New("sevalla: precondition failed")
+	ErrUnprocessableEntity = errors.New("sevalla: unprocessable entity")
+	ErrRateLimited         = errors.New("sevalla: rate limited")
+	ErrServerError         = errors.New("sevalla: server error")
+
+	// ErrValidation is matched by all *ValidationErrors, whether found by
+	// the API or before sending
+	ErrValidation = errors.New("sevalla: validation failed")
+)
+
+// statusSentinels maps response status codes to their sentinel errors
+var statusSentinels = map[int]error{
+	http.StatusBadRequest:          ErrBadRequest,
+	http.StatusUnauthorized:        ErrUnauthorized,
+	http.StatusForbidden:           ErrForbidden,
+	http.StatusNotFound:            ErrNotFound,
+	http.StatusConflict:            ErrConflict,
+	http.StatusPreconditionFailed:  ErrPreconditionFailed,
+	http.StatusUnprocessableEntity: ErrUnprocessableEntity,
+	http.StatusTooManyRequests:     ErrRateLimited,
+}
+
+// temporaryStatusCodes are the response status codes worth retrying
+var temporaryStatusCodes = []int{
+	http.StatusTooManyRequests,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// Is reports whether the error matches target, one of the sentinel errors
+// for its status code
+func (e *ErrorResponse) Is(target error) bool {
+	if e == nil || e.Response == nil {
+		return false
+	}
+
+	if target == ErrServerError {
+		return e.Response.StatusCode >= 500 && e.Response.StatusCode < 600
+	}
+
+	sentinel, ok := statusSentinels[e.Response.StatusCode]
+	return ok && sentinel == target
+}
+
+// Temporary reports whether the request may succeed if retried, i.e. the
+// API was rate limiting or temporarily unavailable
+func (e *ErrorResponse) Temporary() bool {
+	return e != nil && e.Response != nil && slices.Contains(temporaryStatusCodes, e.Response.StatusCode)
+}
+
+// asErrorResponse extracts the underlying ErrorResponse from an API error,
+// looking through wrapped errors
+func asErrorResponse(err error) (*ErrorResponse, bool) {
+	var e *ErrorResponse
+	if !errors.As(err, &e) {
+		return nil, false
+	}
+	return e, e != nil && e.Response != nil
+}
+
 // IsNotFound returns true if the error is a 404 Not Found
 func IsNotFound(err error) bool {
-	if e, ok := err.(*ErrorResponse); ok {
-		return e.Response.StatusCode == http.StatusNotFound
-	}
-	return false
+	return errors.Is(err, ErrNotFound)
 }
 
 // IsBadRequest returns true if the error is a 400 Bad Request
 func IsBadRequest(err error) bool {
-	if e, ok := err.(*ErrorResponse); ok {
-		return e.Response.StatusCode == http.StatusBadRequest
-	}
-	return false
+	return errors.Is(err, ErrBadRequest)
 }
 
 // IsUnauthorized returns true if the error is a 401 Unauthorized
 func IsUnauthorized(err error) bool {
-	if e, ok := err.(*ErrorResponse); ok {
-		return e.Response.StatusCode == http.StatusUnauthorized
-	}
-	return false
+	return errors.Is(err, ErrUnauthorized)
 }
 
 // IsForbidden returns true if the error is a 403 Forbidden
 func IsForbidden(err error) bool {
-	if e, ok := err.(*ErrorResponse); ok {
-		return e.Response.StatusCode == http.StatusForbidden
-	}
-	return false
+	return errors.Is(err, ErrForbidden)
 }
 
 // IsConflict returns true if the error is a 409 Conflict
 func IsConflict(err error) bool {
-	if e, ok := err.(*ErrorResponse); ok {
-		return e.Response.StatusCode == http.StatusConflict
-	}
-	return false
+	return errors.Is(err, ErrConflict)
 }
 
 // IsUnprocessableEntity returns true if the error is a 422 Unprocessable Entity
 func IsUnprocessableEntity(err error) bool {
-	if e, ok := err.(*ErrorResponse); ok {
-		return e.Response.StatusCode == http.StatusUnprocessableEntity
-	}
-	return false
+	return errors.Is(err, ErrUnprocessableEntity)
 }
 
 // IsRateLimited returns true if the error is a 429 Too Many Requests
 func IsRateLimited(err error) bool {
-	if e, ok := err.(*ErrorResponse); ok {
-		return e.Response.StatusCode == http.StatusTooManyRequests
-	}
-	return false
+	return errors.Is(err, ErrRateLimited)
 }
 
 // IsServerError returns true if the error is a 5xx server error
 func IsServerError(err error) bool {
-	if e, ok := err.(*ErrorResponse); ok {
-		return e.Response.StatusCode >= 500 && e.Response.StatusCode < 600
-	}
-	return false
+	return errors.Is(err, ErrServerError)
 }
 
 // IsClientError returns true if the error is a 4xx client error
 func IsClientError(err error) bool {
-	if e, ok := err.(*ErrorResponse); ok {
+	if e, ok := asErrorResponse(err); ok {
 		return e.Response.StatusCode >= 400 && e.Response.StatusCode < 500
 	}
 	return false
 }
 
+// IsTemporary returns true if the request that failed with err may succeed
+// if retried: API errors that are rate limited or temporarily unavailable,
+// and network timeouts. Cancelled or expired contexts are not temporary.
+func IsTemporary(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var temporary interface{ Temporary() bool }
+	if errors.As(err, &temporary) && temporary.Temporary() {
+		return true
+	}
+
+	var timeout interface{ Timeout() bool }
+	return errors.As(err, &timeout) && timeout.Timeout()
+}
+
 // ValidationError represents a validation error
 type ValidationError struct {
-	Field   string
-	Message string
+	Field    string // Go field path in the request struct, e.g. "RepositoryURL"
+	APIField string // Field path as named by the API, e.g. "repository_url"
+	Code     string
+	Message  string
 }
 
 // Error returns the validation error message
@@ -120,10 +186,88 @@ func (e *ValidationError) Error() string {
 // RateLimitError represents a rate limit error
 type RateLimitError struct {
 	*ErrorResponse
-	RetryAfter int // Seconds to wait before retrying
+	Rate       Rate // Rate limit state reported with the error
+	RetryAfter int  // Seconds to wait before retrying
 }
 
 // Error returns the rate limit error message
 func (e *RateLimitError) Error() string {
 	return fmt.Sprintf("rate limited: retry after %d seconds - %s", e.RetryAfter, e.Message)
 }
+
+// Unwrap returns the underlying ErrorResponse
+func (e *RateLimitError) Unwrap() error {
+	return e.ErrorResponse
+}
+
+// DecodeError is returned when a response body cannot be decoded
+type DecodeError struct {
+	Method     string
+	URL        string
+	StatusCode int
+	Body       string // The start of the response body
+	Err        error  // The underlying decoding error
+}
+
+// maxDecodeErrorBody is the number of body bytes kept in a DecodeError
+const maxDecodeErrorBody = 256
+
+// newDecodeError builds a DecodeError for a response with the given body
+func newDecodeError(resp *http.Response, body []byte, err error) *DecodeError {
+	e := &DecodeError{StatusCode: resp.StatusCode, Err: err}
+	if resp.Request != nil {
+		e.Method = resp.Request.Method
+		e.URL = resp.Request.URL.String()
+	}
+
+	if len(body) > maxDecodeErrorBody {
+		e.Body = string(body[:maxDecodeErrorBody]) + "..."
+	} else {
+		e.Body = string(body)
+	}
+
+	return e
+}
+
+// Error returns the decode error message
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("sevalla: decoding %s %s response (%d): %v - body: %q", e.Method, e.URL, e.StatusCode, e.Err, e.Body)
+}
+
+// Unwrap returns the underlying decoding error
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// DeploymentError is returned when a deployment being waited on ends in a
+// failed or cancelled state
+type DeploymentError struct {
+	Deployment *Deployment
+}
+
+// Error returns the deployment error message
+func (e *DeploymentError) Error() string {
+	if e.Deployment.ErrorMessage != "" {
+		return fmt.Sprintf("sevalla: deployment %s %s - %s", e.Deployment.ID, e.Deployment.State, e.Deployment.ErrorMessage)
+	}
+
+	return fmt.Sprintf("sevalla: deployment %s %s", e.Deployment.ID, e.Deployment.State)
+}
+
+// StateError is returned by a waiter when the resource it is waiting on
+// reaches a terminal failure state
+type StateError struct {
+	Resource string // Kind of resource, e.g. "application"
+	ID       string
+	State    string
+	Message  string
+}
+
+// Error returns the state error message
+func (e *StateError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("sevalla: %s %s reached state %s - %s", e.Resource, e.ID, e.State, e.Message)
+	}
+
+	return fmt.Sprintf("sevalla: %s %s reached state %s", e.Resource, e.ID, e.State)
+}
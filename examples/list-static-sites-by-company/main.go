@@ -10,25 +10,24 @@ import (
 )
 
 func main() {
-	// Get API key from environment variable
-	apiKey := os.Getenv("SEVALLA_API_KEY")
-	if apiKey == "" {
-		log.Fatal("SEVALLA_API_KEY environment variable is required")
+	// Load the API key and company ID from SEVALLA_API_KEY and
+	// SEVALLA_COMPANY_ID, or from the Sevalla config file
+	cfg, err := sevalla.LoadConfig()
+	if err != nil {
+		log.Fatal(err)
 	}
 
-	// Get company ID from environment variable or command line argument
-	companyID := os.Getenv("SEVALLA_COMPANY_ID")
-	if companyID == "" && len(os.Args) > 1 {
+	// A command line argument overrides the configured company ID
+	companyID := cfg.CompanyID
+	if len(os.Args) > 1 {
 		companyID = os.Args[1]
 	}
 	if companyID == "" {
-		log.Fatal("SEVALLA_COMPANY_ID environment variable or command line argument is required")
+		log.Fatal("SEVALLA_COMPANY_ID, company_id in the config file or a command line argument is required")
 	}
 
 	// Create client
-	client := sevalla.NewClient(
-		sevalla.WithAPIKey(apiKey),
-	)
+	client := sevalla.NewClient(cfg.Options()...)
 
 	// List static sites for a specific company
 	ctx := context.Background()
@@ -5,7 +5,6 @@ import (
 	"flag"
 	"fmt"
 	"log"
-	"os"
 	"strings"
 	"time"
 
@@ -14,21 +13,21 @@ import (
 
 func main() {
 	var (
-		apiKey  = flag.String("api-key", os.Getenv("SEVALLA_API_KEY"), "Sevalla API key")
+		profile = flag.String("profile", "", "Profile to load from the Sevalla config file")
 		action  = flag.String("action", "list", "Action to perform (list, create, deploy, logs)")
 		appName = flag.String("app", "", "Application name")
 		repoURL = flag.String("repo", "", "Repository URL for new app")
 	)
 	flag.Parse()
 
-	if *apiKey == "" {
-		log.Fatal("API key is required (set SEVALLA_API_KEY or use -api-key flag)")
+	// Load the API key from SEVALLA_API_KEY or the selected profile
+	cfg, err := sevalla.LoadConfig(sevalla.ConfigProfile(*profile))
+	if err != nil {
+		log.Fatal(err)
 	}
 
 	// Create client
-	client := sevalla.NewClient(
-		sevalla.WithAPIKey(*apiKey),
-	)
+	client := sevalla.NewClient(cfg.Options()...)
 
 	ctx := context.Background()
 
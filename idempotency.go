@@ -0,0 +1,103 @@
+package sevalla
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Idempotency headers used by the API
+const (
+	headerIdempotencyKey     = "Idempotency-Key"
+	headerIdempotentReplayed = "Idempotent-Replayed"
+	idempotencyErrorPrefix   = "idempotency"
+)
+
+// ContextWithIdempotencyKey returns a context that makes every POST request
+// sent with it carry the given Idempotency-Key instead of a generated one.
+// Reissuing a failed call with the same context, or the same key after a
+// process restart, lets the API recognise it. Use a context per logical
+// call, as unrelated calls sharing a key are rejected. It is equivalent to
+// WithRequestOptions with RequestIdempotencyKey.
+func ContextWithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return WithRequestOptions(ctx, RequestIdempotencyKey(key))
+}
+
+// IdempotencyKeyFromContext returns the idempotency key set on ctx, if any
+func IdempotencyKeyFromContext(ctx context.Context) (string, bool) {
+	if o := requestOptionsFromContext(ctx); o != nil && o.idempotencyKey != "" {
+		return o.idempotencyKey, true
+	}
+	return "", false
+}
+
+// NewIdempotencyKey returns a random version 4 UUID suitable as an
+// idempotency key
+func NewIdempotencyKey() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// setIdempotencyKey attaches an idempotency key to mutating POST requests.
+// The key is set once per logical call, so retries of the same request
+// reuse it.
+func setIdempotencyKey(ctx context.Context, req *http.Request) {
+	if req.Method != http.MethodPost {
+		return
+	}
+
+	key, ok := IdempotencyKeyFromContext(ctx)
+	if !ok {
+		key = NewIdempotencyKey()
+	}
+
+	req.Header.Set(headerIdempotencyKey, key)
+}
+
+// IdempotencyError is returned when the API rejects the idempotency key of a
+// request, because a request with the same key is still being processed or
+// was sent with different parameters
+type IdempotencyError struct {
+	*ErrorResponse
+	Key string // The rejected idempotency key
+}
+
+// Error returns the idempotency error message
+func (e *IdempotencyError) Error() string {
+	return fmt.Sprintf("sevalla: idempotency key %s rejected (%d) - %s", e.Key, e.Response.StatusCode, e.Message)
+}
+
+// Unwrap returns the underlying ErrorResponse
+func (e *IdempotencyError) Unwrap() error {
+	return e.ErrorResponse
+}
+
+// IsIdempotencyError returns true if the API rejected the idempotency key of
+// a request
+func IsIdempotencyError(err error) bool {
+	var e *IdempotencyError
+	return errors.As(err, &e)
+}
+
+// asIdempotencyError wraps an error response about the request's
+// idempotency key in an *IdempotencyError
+func asIdempotencyError(e *ErrorResponse) (*IdempotencyError, bool) {
+	if e.Response == nil || e.Response.Request == nil {
+		return nil, false
+	}
+
+	key := e.Response.Request.Header.Get(headerIdempotencyKey)
+	if key == "" || !strings.HasPrefix(e.Code, idempotencyErrorPrefix) {
+		return nil, false
+	}
+
+	return &IdempotencyError{ErrorResponse: e, Key: key}, true
+}
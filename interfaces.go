@@ -0,0 +1,122 @@
+package sevalla
+
+import (
+	"context"
+	"iter"
+)
+
+// ApplicationsAPI is the interface implemented by ApplicationsService. Depend on it
+// instead of the concrete service to substitute test doubles.
+type ApplicationsAPI interface {
+	List(ctx context.Context, opts *ListOptions) ([]*Application, *Response, error)
+	ListIter(ctx context.Context, opts *ListOptions) iter.Seq2[*Application, error]
+	ListAll(ctx context.Context, opts *ListOptions) ([]*Application, error)
+	Get(ctx context.Context, id string) (*Application, *Response, error)
+	Create(ctx context.Context, createReq *CreateApplicationRequest) (*Application, *Response, error)
+	Update(ctx context.Context, id string, updateReq *UpdateApplicationRequest) (*Application, *Response, error)
+	Modify(ctx context.Context, id string, mutate func(app *Application) (*UpdateApplicationRequest, error)) (*Application, *Response, error)
+	Delete(ctx context.Context, id string) (*Response, error)
+	Scale(ctx context.Context, id string, scaleReq *ScaleApplicationRequest) (*Application, *Response, error)
+	Deploy(ctx context.Context, id string) (*Deployment, *Response, error)
+	Restart(ctx context.Context, id string) (*Response, error)
+	Stop(ctx context.Context, id string) (*Response, error)
+	Start(ctx context.Context, id string) (*Response, error)
+	GetLogs(ctx context.Context, id string, lines int) (string, *Response, error)
+	TailLogs(ctx context.Context, id string, opts *TailLogsOptions) (*LogStream, error)
+	ListDeployments(ctx context.Context, id string, opts *ListOptions) ([]*Deployment, *Response, error)
+	ListDeploymentsIter(ctx context.Context, id string, opts *ListOptions) iter.Seq2[*Deployment, error]
+	ListAllDeployments(ctx context.Context, id string, opts *ListOptions) ([]*Deployment, error)
+	GetDeployment(ctx context.Context, appID string, deploymentID string) (*Deployment, *Response, error)
+	CancelDeployment(ctx context.Context, appID string, deploymentID string) (*Response, error)
+	AddCustomDomain(ctx context.Context, id string, domain string) (*Response, error)
+	RemoveCustomDomain(ctx context.Context, id string, domain string) (*Response, error)
+	UpdateCDNSettings(ctx context.Context, id string, enabled bool) (*Response, error)
+	GetUsage(ctx context.Context, id string, period string) (*Usage, *Response, error)
+	SetEnvironmentVariables(ctx context.Context, id string, vars map[string]string) (*Response, error)
+	GetEnvironmentVariables(ctx context.Context, id string) (map[string]Secret, *Response, error)
+	Rollback(ctx context.Context, appID string, deploymentID string) (*Deployment, *Response, error)
+	WaitForState(ctx context.Context, id string, state ApplicationState, opts *WaitOptions[*Application]) (*Application, error)
+}
+
+// DatabasesAPI is the interface implemented by DatabasesService. Depend on it
+// instead of the concrete service to substitute test doubles.
+type DatabasesAPI interface {
+	List(ctx context.Context, opts *ListOptions) ([]*Database, *Response, error)
+	ListIter(ctx context.Context, opts *ListOptions) iter.Seq2[*Database, error]
+	ListAll(ctx context.Context, opts *ListOptions) ([]*Database, error)
+	Get(ctx context.Context, id string) (*Database, *Response, error)
+	Create(ctx context.Context, createReq *CreateDatabaseRequest) (*Database, *Response, error)
+	Update(ctx context.Context, id string, updateReq *UpdateDatabaseRequest) (*Database, *Response, error)
+	Modify(ctx context.Context, id string, mutate func(database *Database) (*UpdateDatabaseRequest, error)) (*Database, *Response, error)
+	Delete(ctx context.Context, id string) (*Response, error)
+	GetCredentials(ctx context.Context, id string) (*Database, *Response, error)
+	ResetPassword(ctx context.Context, id string) (*Database, *Response, error)
+	ListBackups(ctx context.Context, id string, opts *ListOptions) ([]*Backup, *Response, error)
+	ListBackupsIter(ctx context.Context, id string, opts *ListOptions) iter.Seq2[*Backup, error]
+	ListAllBackups(ctx context.Context, id string, opts *ListOptions) ([]*Backup, error)
+	CreateBackup(ctx context.Context, id string, backupReq *CreateBackupRequest) (*Backup, *Response, error)
+	GetBackup(ctx context.Context, dbID string, backupID string) (*Backup, *Response, error)
+	DeleteBackup(ctx context.Context, dbID string, backupID string) (*Response, error)
+	RestoreFromBackup(ctx context.Context, id string, restoreReq *RestoreBackupRequest) (*Response, error)
+	GetUsage(ctx context.Context, id string, period string) (*Usage, *Response, error)
+	EnablePublicAccess(ctx context.Context, id string) (*Database, *Response, error)
+	DisablePublicAccess(ctx context.Context, id string) (*Database, *Response, error)
+	WaitUntilReady(ctx context.Context, id string, opts *WaitOptions[*Database]) (*Database, error)
+	WaitForBackup(ctx context.Context, dbID string, backupID string, opts *WaitOptions[*Backup]) (*Backup, error)
+}
+
+// StaticSitesAPI is the interface implemented by StaticSitesService. Depend on it
+// instead of the concrete service to substitute test doubles.
+type StaticSitesAPI interface {
+	List(ctx context.Context, opts *ListOptions) ([]*StaticSite, *Response, error)
+	ListIter(ctx context.Context, opts *ListOptions) iter.Seq2[*StaticSite, error]
+	ListAll(ctx context.Context, opts *ListOptions) ([]*StaticSite, error)
+	Get(ctx context.Context, id string) (*StaticSite, *Response, error)
+	Create(ctx context.Context, createReq *CreateStaticSiteRequest) (*StaticSite, *Response, error)
+	Delete(ctx context.Context, id string) (*Response, error)
+	Deploy(ctx context.Context, id string) (*Deployment, *Response, error)
+}
+
+// DeploymentsAPI is the interface implemented by DeploymentsService. Depend on it
+// instead of the concrete service to substitute test doubles.
+type DeploymentsAPI interface {
+	Get(ctx context.Context, id string) (*Deployment, *Response, error)
+	List(ctx context.Context, opts *ListOptions) ([]*Deployment, *Response, error)
+	ListIter(ctx context.Context, opts *ListOptions) iter.Seq2[*Deployment, error]
+	ListAll(ctx context.Context, opts *ListOptions) ([]*Deployment, error)
+	GetLogs(ctx context.Context, id string) (string, *Response, error)
+	TailLogs(ctx context.Context, id string, opts *TailLogsOptions) (*LogStream, error)
+	Cancel(ctx context.Context, id string) (*Response, error)
+	WaitForDeployment(ctx context.Context, id string, opts *WaitOptions[*Deployment]) (*Deployment, error)
+}
+
+// PipelinesAPI is the interface implemented by PipelinesService. Depend on it
+// instead of the concrete service to substitute test doubles.
+type PipelinesAPI interface {
+	List(ctx context.Context, opts *ListOptions) ([]*Pipeline, *Response, error)
+	ListIter(ctx context.Context, opts *ListOptions) iter.Seq2[*Pipeline, error]
+	ListAll(ctx context.Context, opts *ListOptions) ([]*Pipeline, error)
+	Get(ctx context.Context, id string) (*Pipeline, *Response, error)
+	Create(ctx context.Context, createReq *CreatePipelineRequest) (*Pipeline, *Response, error)
+	Update(ctx context.Context, id string, updateReq *UpdatePipelineRequest) (*Pipeline, *Response, error)
+	Modify(ctx context.Context, id string, mutate func(pipeline *Pipeline) (*UpdatePipelineRequest, error)) (*Pipeline, *Response, error)
+	Delete(ctx context.Context, id string) (*Response, error)
+	Run(ctx context.Context, id string) (*PipelineRun, *Response, error)
+	ListRuns(ctx context.Context, pipelineID string, opts *ListOptions) ([]*PipelineRun, *Response, error)
+	ListRunsIter(ctx context.Context, pipelineID string, opts *ListOptions) iter.Seq2[*PipelineRun, error]
+	ListAllRuns(ctx context.Context, pipelineID string, opts *ListOptions) ([]*PipelineRun, error)
+	GetRun(ctx context.Context, pipelineID string, runID string) (*PipelineRun, *Response, error)
+	CancelRun(ctx context.Context, pipelineID string, runID string) (*Response, error)
+	GetRunLogs(ctx context.Context, pipelineID string, runID string) (string, *Response, error)
+	RetryRun(ctx context.Context, pipelineID string, runID string) (*PipelineRun, *Response, error)
+	WaitForRun(ctx context.Context, pipelineID string, runID string, opts *WaitOptions[*PipelineRun]) (*PipelineRun, error)
+}
+
+// Compile-time assertions that the services implement their interfaces
+var (
+	_ ApplicationsAPI = (*ApplicationsService)(nil)
+	_ DatabasesAPI    = (*DatabasesService)(nil)
+	_ StaticSitesAPI  = (*StaticSitesService)(nil)
+	_ DeploymentsAPI  = (*DeploymentsService)(nil)
+	_ PipelinesAPI    = (*PipelinesService)(nil)
+)
@@ -0,0 +1,199 @@
+package sevalla
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// redacted replaces secret values in log output
+const redacted = "[REDACTED]"
+
+// WithLogger logs every attempt of every API call to the given logger.
+// Successful calls are logged at Info, 4xx responses at Warn, and 5xx
+// responses and transport errors at Error. At Debug, the request headers and
+// the request and response bodies are included as well, with the
+// Authorization header, passwords and environment variable values redacted.
+func WithLogger(logger *slog.Logger) ClientOption {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
+
+// LoggingMiddleware logs each request passing through it. It is installed
+// by WithLogger inside the retry middleware, so every attempt is logged.
+func LoggingMiddleware(logger *slog.Logger) Middleware {
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request, v interface{}) (*Response, error) {
+			ctx := req.Context()
+			debug := logger.Enabled(ctx, slog.LevelDebug)
+
+			attrs := []slog.Attr{
+				slog.String("method", req.Method),
+				slog.String("path", req.URL.Path),
+				slog.Int("attempt", RequestAttempt(ctx)),
+			}
+
+			if debug {
+				attrs = append(attrs, slog.Any("request_headers", redactHeaders(req.Header)))
+				if body := requestBody(req); body != nil {
+					attrs = append(attrs, slog.String("request_body", redactBody(req.URL.Path, body)))
+				}
+			}
+
+			start := time.Now()
+			response, err := next.Do(req, v)
+			attrs = append(attrs, slog.Duration("duration", time.Since(start)))
+
+			level := slog.LevelInfo
+			if response != nil {
+				attrs = append(attrs,
+					slog.Int("status", response.StatusCode),
+					slog.Int("rate_limit", response.Rate.Limit),
+					slog.Int("rate_remaining", response.Rate.Remaining),
+				)
+
+				if id := requestID(response, err); id != "" {
+					attrs = append(attrs, slog.String("request_id", id))
+				}
+
+				switch {
+				case response.StatusCode >= 500:
+					level = slog.LevelError
+				case response.StatusCode >= 400:
+					level = slog.LevelWarn
+				}
+
+				if debug && err == nil && v != nil {
+					switch v.(type) {
+					case io.Writer, *streamBody:
+					default:
+						if body, marshalErr := json.Marshal(v); marshalErr == nil {
+							attrs = append(attrs, slog.String("response_body", redactBody(req.URL.Path, body)))
+						}
+					}
+				}
+			}
+
+			if err != nil {
+				attrs = append(attrs, slog.String("error", err.Error()))
+				if response == nil {
+					level = slog.LevelError
+				}
+			}
+
+			logger.LogAttrs(ctx, level, "sevalla request", attrs...)
+
+			return response, err
+		})
+	}
+}
+
+// requestID returns the request ID reported by the API for a response
+func requestID(response *Response, err error) string {
+	if id := response.Header.Get("X-Request-Id"); id != "" {
+		return id
+	}
+
+	if e, ok := asErrorResponse(err); ok {
+		return e.RequestID
+	}
+
+	return ""
+}
+
+// requestBody returns a copy of the request body without consuming it
+func requestBody(req *http.Request) []byte {
+	if req.GetBody == nil || req.Body == nil || req.Body == http.NoBody {
+		return nil
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil
+	}
+	defer func() {
+		_ = body.Close()
+	}()
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil
+	}
+
+	return data
+}
+
+// redactHeaders returns a copy of the headers with credentials redacted
+func redactHeaders(h http.Header) http.Header {
+	h = h.Clone()
+	if h.Get("Authorization") != "" {
+		h.Set("Authorization", redacted)
+	}
+
+	return h
+}
+
+// redactBody returns a JSON body with passwords and environment variable
+// values redacted. Bodies of the environment variable endpoints hold nothing
+// but variables, so all of their values are redacted.
+func redactBody(path string, body []byte) string {
+	var data interface{}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return redacted
+	}
+
+	if strings.HasSuffix(path, "/env") {
+		data = redactValues(data)
+	} else {
+		data = redactSecrets(data)
+	}
+
+	out, err := json.Marshal(data)
+	if err != nil {
+		return redacted
+	}
+
+	return string(out)
+}
+
+// redactSecrets redacts database credentials and environment variables in
+// decoded JSON
+func redactSecrets(data interface{}) interface{} {
+	switch v := data.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			switch strings.ToLower(key) {
+			case "password", "username":
+				v[key] = redacted
+			case "environment_variables", "environment":
+				v[key] = redactValues(value)
+			default:
+				v[key] = redactSecrets(value)
+			}
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = redactSecrets(value)
+		}
+	}
+
+	return data
+}
+
+// redactValues redacts every value of a decoded JSON object, keeping its keys
+func redactValues(data interface{}) interface{} {
+	vars, ok := data.(map[string]interface{})
+	if !ok {
+		return redacted
+	}
+
+	for key := range vars {
+		vars[key] = redacted
+	}
+
+	return vars
+}
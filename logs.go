@@ -0,0 +1,436 @@
+package sevalla
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultLogReconnects is the default number of consecutive reconnects
+	// attempted when a log stream is interrupted
+	DefaultLogReconnects = 5
+
+	// DefaultLogReconnectDelay is the default delay before reconnecting an
+	// interrupted log stream
+	DefaultLogReconnectDelay = time.Second
+
+	// maxLogLineSize bounds the size of a single log line or event
+	maxLogLineSize = 1024 * 1024
+)
+
+// LogLine represents a single structured log entry
+type LogLine struct {
+	Timestamp time.Time `json:"timestamp"`
+	Stream    string    `json:"stream,omitempty"` // e.g. "stdout", "stderr" or "build"
+	Message   string    `json:"message"`
+
+	// Cursor identifies the position of the line in the stream. Pass it
+	// as TailLogsOptions.Cursor to resume after this line.
+	Cursor string `json:"cursor,omitempty"`
+}
+
+// TailLogsOptions configures a log stream
+type TailLogsOptions struct {
+	// Since only returns lines logged at or after this time
+	Since time.Time
+
+	// Until only returns lines logged before this time
+	Until time.Time
+
+	// Cursor resumes the stream after the line with this cursor
+	Cursor string
+
+	// MaxReconnects is the number of consecutive reconnects attempted when
+	// the stream is interrupted. Defaults to DefaultLogReconnects, negative
+	// values disable reconnecting.
+	MaxReconnects int
+
+	// ReconnectDelay is the delay before reconnecting. Defaults to
+	// DefaultLogReconnectDelay.
+	ReconnectDelay time.Duration
+}
+
+// logStreamQuery holds the query parameters of a log stream request
+type logStreamQuery struct {
+	Since  string `url:"since,omitempty"`
+	Until  string `url:"until,omitempty"`
+	Cursor string `url:"cursor,omitempty"`
+}
+
+// LogStream delivers log lines as they are written. Lines are read from
+// Lines until the channel is closed, after which Err reports why the stream
+// ended. Close must be called to release the underlying connection.
+type LogStream struct {
+	lines  chan LogLine
+	done   chan struct{}
+	cancel context.CancelFunc
+
+	mu     sync.Mutex
+	err    error
+	closed bool
+}
+
+// Lines returns the channel log lines are delivered on. It is closed when
+// the stream ends.
+func (s *LogStream) Lines() <-chan LogLine {
+	return s.lines
+}
+
+// Err returns the error that ended the stream, or nil if the logs were
+// fully read or the stream was closed
+func (s *LogStream) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.err
+}
+
+// Close stops the stream and waits for it to shut down
+func (s *LogStream) Close() error {
+	s.mu.Lock()
+	s.closed = true
+	s.mu.Unlock()
+
+	s.cancel()
+	<-s.done
+
+	return nil
+}
+
+// setErr records the error that ended the stream unless it was closed
+func (s *LogStream) setErr(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.closed {
+		s.err = err
+	}
+}
+
+// logTailer connects to a log stream endpoint and tracks the resume position
+type logTailer struct {
+	client *Client
+	path   string
+	opts   TailLogsOptions
+
+	// Operation and span attributes of every connect
+	operation string
+	attrs     []Attribute
+
+	// Position of the last delivered line
+	cursor string
+	since  time.Time
+
+	// Lines delivered with the timestamp since, which a resume without a
+	// cursor receives again as Since is inclusive
+	atSince map[string]int
+}
+
+// tailLogs opens a log stream on the given endpoint. Each connect is traced
+// as the given operation.
+func (c *Client) tailLogs(ctx context.Context, operation, path string, opts *TailLogsOptions, attrs ...Attribute) (*LogStream, error) {
+	t := &logTailer{client: c, path: path, operation: operation, attrs: attrs}
+	if opts != nil {
+		t.opts = *opts
+	}
+	if t.opts.MaxReconnects == 0 {
+		t.opts.MaxReconnects = DefaultLogReconnects
+	}
+	if t.opts.ReconnectDelay <= 0 {
+		t.opts.ReconnectDelay = DefaultLogReconnectDelay
+	}
+	t.cursor = t.opts.Cursor
+	t.since = t.opts.Since
+	t.atSince = map[string]int{}
+
+	ctx, cancel := context.WithCancel(ctx)
+
+	resp, err := t.connect(ctx)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	stream := &LogStream{
+		lines:  make(chan LogLine),
+		done:   make(chan struct{}),
+		cancel: cancel,
+	}
+	go t.run(ctx, stream, resp)
+
+	return stream, nil
+}
+
+// connect opens a connection to the log stream, resuming after the last
+// delivered line
+func (t *logTailer) connect(ctx context.Context) (*http.Response, error) {
+	ctx, span := t.client.startSpan(ctx, t.operation, t.attrs...)
+	defer span.End()
+
+	q := &logStreamQuery{Cursor: t.cursor}
+	if !t.since.IsZero() {
+		q.Since = t.since.UTC().Format(time.RFC3339Nano)
+	}
+	if !t.opts.Until.IsZero() {
+		q.Until = t.opts.Until.UTC().Format(time.RFC3339Nano)
+	}
+
+	req, err := t.client.NewRequestWithQuery(ctx, "GET", t.path, q)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Accept", "text/event-stream, application/x-ndjson")
+	if t.cursor != "" {
+		req.Header.Set("Last-Event-ID", t.cursor)
+	}
+
+	// Sent through the middleware chain but not Do, as a request timeout
+	// would end the stream when the connection is established
+	var stream streamBody
+	resp, err := t.client.doer.Do(req, &stream)
+	if err != nil {
+		return nil, err
+	}
+
+	streamed := *resp.Response
+	streamed.Body = stream.body
+	return &streamed, nil
+}
+
+// run reads the stream, reconnecting after interruptions
+func (t *logTailer) run(ctx context.Context, stream *LogStream, resp *http.Response) {
+	defer close(stream.done)
+	defer close(stream.lines)
+	defer stream.cancel()
+
+	reconnects := 0
+	for {
+		delivered, finished, err := t.read(ctx, resp, stream.lines)
+		_ = resp.Body.Close()
+
+		if finished {
+			return
+		}
+		if ctx.Err() != nil {
+			stream.setErr(ctx.Err())
+			return
+		}
+
+		if delivered > 0 {
+			reconnects = 0
+		}
+
+		for {
+			if reconnects >= t.opts.MaxReconnects {
+				stream.setErr(fmt.Errorf("sevalla: log stream interrupted: %w", err))
+				return
+			}
+			reconnects++
+
+			if !sleepContext(ctx, t.opts.ReconnectDelay) {
+				stream.setErr(fmt.Errorf("sevalla: log stream interrupted: %w", err))
+				return
+			}
+
+			resp, err = t.connect(ctx)
+			if err == nil {
+				break
+			}
+
+			// Errors such as a missing resource will not go away on retry
+			if IsClientError(err) && !IsRateLimited(err) {
+				stream.setErr(err)
+				return
+			}
+		}
+	}
+}
+
+// read delivers the lines of a single connection. It reports whether the
+// stream ended normally, or the error that interrupted it. An event stream
+// ends normally with an end event, a newline-delimited stream at EOF.
+func (t *logTailer) read(ctx context.Context, resp *http.Response, out chan<- LogLine) (int, bool, error) {
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLogLineSize)
+
+	sse := strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream")
+	delivered := 0
+
+	// Without a cursor the connection starts at the timestamp of the last
+	// delivered line, so the lines already delivered are skipped
+	var replayed map[string]int
+	if t.cursor == "" {
+		replayed = maps.Clone(t.atSince)
+	}
+
+	send := func(line LogLine) bool {
+		if !t.opts.Until.IsZero() && !line.Timestamp.IsZero() && !line.Timestamp.Before(t.opts.Until) {
+			return false
+		}
+
+		key := line.Stream + "\x00" + line.Message
+		if replayed != nil && !line.Timestamp.IsZero() {
+			if line.Timestamp.Before(t.since) {
+				return true
+			}
+			if line.Timestamp.Equal(t.since) && replayed[key] > 0 {
+				replayed[key]--
+				return true
+			}
+		}
+
+		select {
+		case out <- line:
+		case <-ctx.Done():
+			return false
+		}
+
+		delivered++
+		if line.Cursor != "" {
+			t.cursor = line.Cursor
+		}
+		switch {
+		case line.Timestamp.IsZero():
+		case line.Timestamp.Equal(t.since):
+			t.atSince[key]++
+		case line.Timestamp.After(t.since):
+			t.since = line.Timestamp
+			t.atSince = map[string]int{key: 1}
+		}
+		return true
+	}
+
+	var (
+		event string
+		id    string
+		data  []string
+	)
+
+	for scanner.Scan() {
+		text := scanner.Text()
+
+		if !sse {
+			if strings.TrimSpace(text) == "" {
+				continue
+			}
+			if !send(parseLogLine(text)) {
+				return delivered, ctx.Err() == nil, ctx.Err()
+			}
+			continue
+		}
+
+		// Server-Sent Events: fields until a blank line dispatches the event
+		if text == "" {
+			if event == "end" {
+				return delivered, true, nil
+			}
+			if len(data) > 0 {
+				line := parseLogLine(strings.Join(data, "\n"))
+				if id != "" {
+					line.Cursor = id
+				}
+				if !send(line) {
+					return delivered, ctx.Err() == nil, ctx.Err()
+				}
+			}
+			event, id, data = "", "", nil
+			continue
+		}
+
+		field, value, _ := strings.Cut(text, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "event":
+			event = value
+		case "id":
+			id = value
+		case "data":
+			data = append(data, value)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return delivered, false, err
+	}
+
+	if ctx.Err() != nil {
+		return delivered, false, ctx.Err()
+	}
+
+	// An event stream is complete only once the end event was sent, a
+	// connection closed before it was cut short, e.g. by a proxy
+	if sse && event != "end" {
+		return delivered, false, io.ErrUnexpectedEOF
+	}
+
+	return delivered, true, nil
+}
+
+// parseLogLine parses a JSON log entry, falling back to a plain text line
+// optionally prefixed with an RFC 3339 timestamp and a stream name
+func parseLogLine(text string) LogLine {
+	var line LogLine
+	if strings.HasPrefix(strings.TrimSpace(text), "{") {
+		if err := json.Unmarshal([]byte(text), &line); err == nil {
+			return line
+		}
+	}
+
+	line = LogLine{Message: text}
+
+	ts, rest, ok := strings.Cut(text, " ")
+	if !ok {
+		return line
+	}
+	t, err := time.Parse(time.RFC3339Nano, ts)
+	if err != nil {
+		return line
+	}
+	line.Timestamp = t
+	line.Message = rest
+
+	if stream, msg, ok := strings.Cut(rest, " "); ok && (stream == "stdout" || stream == "stderr") {
+		line.Stream = stream
+		line.Message = msg
+	}
+
+	return line
+}
+
+// streamBody receives the unread body of a streamed response. Passed as
+// the value to decode into, it makes the end of the middleware chain hand
+// back the body instead of reading it.
+type streamBody struct {
+	body io.ReadCloser
+}
+
+// sendStream performs a single attempt of a request whose response body is
+// read incrementally by the caller. The client timeout is not applied, the
+// request context controls the lifetime of the stream instead.
+func (c *Client) sendStream(req *http.Request, stream *streamBody) (*Response, error) {
+	streamClient := *c.client
+	streamClient.Timeout = 0
+
+	resp, err := streamClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	response := newResponse(resp)
+	if err := CheckResponse(resp); err != nil {
+		_ = resp.Body.Close()
+		return response, err
+	}
+
+	stream.body = resp.Body
+	return response, nil
+}
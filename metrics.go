@@ -0,0 +1,114 @@
+package sevalla
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Metric names recorded by the client
+const (
+	// MetricRequests counts requests sent, labelled by service, method and
+	// status class
+	MetricRequests = "sevalla_requests_total"
+
+	// MetricRequestErrors counts failed requests, labelled by service,
+	// method and status class
+	MetricRequestErrors = "sevalla_request_errors_total"
+
+	// MetricRequestDuration is a histogram of request latencies in seconds,
+	// labelled by service and method
+	MetricRequestDuration = "sevalla_request_duration_seconds"
+
+	// MetricRetries counts retried requests, labelled by service and method
+	MetricRetries = "sevalla_retries_total"
+
+	// MetricRateLimitRemaining is a gauge of the remaining rate limit quota
+	// reported by the API, set by every response with X-RateLimit-Remaining
+	MetricRateLimitRemaining = "sevalla_rate_limit_remaining"
+)
+
+// Metric label names
+const (
+	LabelService     = "service"
+	LabelMethod      = "method"
+	LabelStatusClass = "status_class"
+)
+
+// Metrics records client metrics. Metrics are labelled by service and
+// method, e.g. "Applications" and "Deploy", never by URL, to keep the
+// number of label values bounded. Implementations must be safe for
+// concurrent use.
+type Metrics interface {
+	// AddCounter adds value to a counter
+	AddCounter(name string, value float64, labels map[string]string)
+
+	// ObserveHistogram records a value in a histogram
+	ObserveHistogram(name string, value float64, labels map[string]string)
+
+	// SetGauge sets a gauge to value
+	SetGauge(name string, value float64, labels map[string]string)
+}
+
+// WithMetrics records request counts, latencies, errors, retries and the
+// remaining rate limit quota for every attempt of every API call
+func WithMetrics(metrics Metrics) ClientOption {
+	return func(c *Client) {
+		c.metrics = metrics
+	}
+}
+
+// operationKey is the context key holding the name of the current operation
+type operationKey struct{}
+
+// requestOperation returns the service and method of the operation that
+// sent a request. Requests sent directly through Client.Do are reported
+// with an empty service and their HTTP method.
+func requestOperation(req *http.Request) (service, method string) {
+	if operation, ok := req.Context().Value(operationKey{}).(string); ok {
+		if service, method, ok := strings.Cut(operation, "."); ok {
+			return service, method
+		}
+	}
+	return "", req.Method
+}
+
+// statusClass returns the class of a response status, e.g. "2xx", or
+// "error" when no response was received
+func statusClass(response *Response) string {
+	if response == nil {
+		return "error"
+	}
+	return strconv.Itoa(response.StatusCode/100) + "xx"
+}
+
+// metricsMiddleware records metrics for each request passing through it
+func metricsMiddleware(metrics Metrics) Middleware {
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request, v interface{}) (*Response, error) {
+			service, method := requestOperation(req)
+			labels := map[string]string{LabelService: service, LabelMethod: method}
+
+			if RequestAttempt(req.Context()) > 1 {
+				metrics.AddCounter(MetricRetries, 1, labels)
+			}
+
+			start := time.Now()
+			response, err := next.Do(req, v)
+			metrics.ObserveHistogram(MetricRequestDuration, time.Since(start).Seconds(), labels)
+
+			statusLabels := map[string]string{LabelService: service, LabelMethod: method, LabelStatusClass: statusClass(response)}
+			metrics.AddCounter(MetricRequests, 1, statusLabels)
+			if err != nil {
+				metrics.AddCounter(MetricRequestErrors, 1, statusLabels)
+			}
+
+			if response != nil && hasRateRemaining(response.Response) {
+				metrics.SetGauge(MetricRateLimitRemaining, float64(response.Rate.Remaining), map[string]string{})
+			}
+
+			return response, err
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package sevalla
+
+import (
+	"context"
+	"net/http"
+)
+
+// Doer sends an API request and decodes the response body into v
+type Doer interface {
+	Do(req *http.Request, v interface{}) (*Response, error)
+}
+
+// DoerFunc is an adapter to allow the use of ordinary functions as a Doer
+type DoerFunc func(req *http.Request, v interface{}) (*Response, error)
+
+// Do calls f(req, v)
+func (f DoerFunc) Do(req *http.Request, v interface{}) (*Response, error) {
+	return f(req, v)
+}
+
+// Middleware wraps a Doer to observe or modify the outgoing request and the
+// decoded Response and error returned by the rest of the chain.
+//
+// A middleware that retries must rewind the request body between attempts.
+type Middleware func(next Doer) Doer
+
+// WithMiddleware appends middlewares to the client's chain. Middlewares run
+// in the order they are added, the first one being the outermost, and all of
+// them wrap the built-in tracing, dry-run, retry, logging, metrics and rate
+// limiting middlewares. Requests retried with refreshed credentials pass through
+// them again. Each call to WithMiddleware adds to the middlewares set by
+// previous calls.
+func WithMiddleware(middlewares ...Middleware) ClientOption {
+	return func(c *Client) {
+		c.middlewares = append(c.middlewares, middlewares...)
+	}
+}
+
+// attemptKey is the context key holding the attempt number of a request
+type attemptKey struct{}
+
+// RequestAttempt returns the 1-based attempt number of the request with the
+// given context. Middlewares that run inside RetryMiddleware see the current
+// attempt, all others see 1.
+func RequestAttempt(ctx context.Context) int {
+	if attempt, ok := ctx.Value(attemptKey{}).(int); ok {
+		return attempt
+	}
+	return 1
+}
+
+// RetryMiddleware retries failed requests according to the given policy,
+// unless the request options override it. The client installs it with the
+// policy set by WithRetryPolicy. It may be added with WithMiddleware instead
+// to control where retries happen in the chain.
+func RetryMiddleware(policy *RetryPolicy) Middleware {
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request, v interface{}) (*Response, error) {
+			policy := requestRetryPolicy(req.Context(), policy)
+			attempts := policy.maxAttempts()
+
+			response, err := next.Do(withAttempt(req, 1), v)
+			for attempt := 1; attempt < attempts && policy.shouldRetry(req, response, err); attempt++ {
+				delay := policy.backoff(attempt)
+				if response != nil && response.Rate.RetryAfter > delay {
+					delay = response.Rate.RetryAfter
+				}
+
+				if !sleepContext(req.Context(), delay) {
+					break
+				}
+
+				if rewindErr := rewindBody(req); rewindErr != nil {
+					break
+				}
+
+				spanFromContext(req.Context()).SetAttributes(Attribute{Key: attrRetryCount, Value: attempt})
+				response, err = next.Do(withAttempt(req, attempt+1), v)
+			}
+
+			return response, err
+		})
+	}
+}
+
+// withAttempt returns a shallow copy of req carrying the attempt number
+func withAttempt(req *http.Request, attempt int) *http.Request {
+	return req.WithContext(context.WithValue(req.Context(), attemptKey{}, attempt))
+}
+
+// RateLimitMiddleware paces requests through the given rate limiter and
+// updates it from the rate limit state of every response. It is installed by
+// WithRateLimiter.
+func RateLimitMiddleware(limiter *RateLimiter) Middleware {
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request, v interface{}) (*Response, error) {
+			if err := limiter.Wait(req.Context()); err != nil {
+				return nil, err
+			}
+
+			response, err := next.Do(req, v)
+			if response != nil {
+				limiter.Update(response.Rate)
+			}
+
+			return response, err
+		})
+	}
+}
+
+// buildChain wraps the transport in the built-in and user middlewares
+func (c *Client) buildChain() Doer {
+	var middlewares []Middleware
+
+	if c.credentials != nil {
+		middlewares = append(middlewares, credentialsMiddleware(c.credentials))
+	}
+
+	middlewares = append(middlewares, c.middlewares...)
+
+	if c.tracer != nil {
+		middlewares = append(middlewares, tracingMiddleware(c.tracer))
+	}
+
+	if c.dryRun != nil {
+		middlewares = append(middlewares, dryRunMiddleware(c.dryRun))
+	}
+
+	// Always installed so that request options can enable retries
+	middlewares = append(middlewares, RetryMiddleware(c.retryPolicy))
+
+	if c.logger != nil {
+		middlewares = append(middlewares, LoggingMiddleware(c.logger))
+	}
+
+	if c.metrics != nil {
+		middlewares = append(middlewares, metricsMiddleware(c.metrics))
+	}
+
+	if c.rateLimiter != nil {
+		middlewares = append(middlewares, RateLimitMiddleware(c.rateLimiter))
+	}
+
+	var doer Doer = DoerFunc(c.send)
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		doer = middlewares[i](doer)
+	}
+
+	return doer
+}
@@ -0,0 +1,809 @@
+// Package mocks provides hand-written test doubles for the sevalla service
+// interfaces. Each mock method delegates to the function field of the same
+// name with a Func suffix, and returns an error when that field is nil.
+//
+//	apps := &mocks.Applications{
+//		GetFunc: func(ctx context.Context, id string) (*sevalla.Application, *sevalla.Response, error) {
+//			return &sevalla.Application{ID: id, State: sevalla.StateRunning}, nil, nil
+//		},
+//	}
+package mocks
+
+import (
+	"context"
+	"fmt"
+	"iter"
+
+	"github.com/juststeveking/sevalla-go"
+)
+
+// ErrNotImplemented is returned by mock methods without a function set
+var ErrNotImplemented = fmt.Errorf("mocks: method not implemented")
+
+// notImplemented returns ErrNotImplemented annotated with the method name
+func notImplemented(method string) error {
+	return fmt.Errorf("%w: %s", ErrNotImplemented, method)
+}
+
+// Applications is a mock implementation of sevalla.ApplicationsAPI
+type Applications struct {
+	ListFunc                    func(context.Context, *sevalla.ListOptions) ([]*sevalla.Application, *sevalla.Response, error)
+	ListIterFunc                func(context.Context, *sevalla.ListOptions) iter.Seq2[*sevalla.Application, error]
+	ListAllFunc                 func(context.Context, *sevalla.ListOptions) ([]*sevalla.Application, error)
+	GetFunc                     func(context.Context, string) (*sevalla.Application, *sevalla.Response, error)
+	CreateFunc                  func(context.Context, *sevalla.CreateApplicationRequest) (*sevalla.Application, *sevalla.Response, error)
+	UpdateFunc                  func(context.Context, string, *sevalla.UpdateApplicationRequest) (*sevalla.Application, *sevalla.Response, error)
+	ModifyFunc                  func(context.Context, string, func(*sevalla.Application) (*sevalla.UpdateApplicationRequest, error)) (*sevalla.Application, *sevalla.Response, error)
+	DeleteFunc                  func(context.Context, string) (*sevalla.Response, error)
+	ScaleFunc                   func(context.Context, string, *sevalla.ScaleApplicationRequest) (*sevalla.Application, *sevalla.Response, error)
+	DeployFunc                  func(context.Context, string) (*sevalla.Deployment, *sevalla.Response, error)
+	RestartFunc                 func(context.Context, string) (*sevalla.Response, error)
+	StopFunc                    func(context.Context, string) (*sevalla.Response, error)
+	StartFunc                   func(context.Context, string) (*sevalla.Response, error)
+	GetLogsFunc                 func(context.Context, string, int) (string, *sevalla.Response, error)
+	TailLogsFunc                func(context.Context, string, *sevalla.TailLogsOptions) (*sevalla.LogStream, error)
+	ListDeploymentsFunc         func(context.Context, string, *sevalla.ListOptions) ([]*sevalla.Deployment, *sevalla.Response, error)
+	ListDeploymentsIterFunc     func(context.Context, string, *sevalla.ListOptions) iter.Seq2[*sevalla.Deployment, error]
+	ListAllDeploymentsFunc      func(context.Context, string, *sevalla.ListOptions) ([]*sevalla.Deployment, error)
+	GetDeploymentFunc           func(context.Context, string, string) (*sevalla.Deployment, *sevalla.Response, error)
+	CancelDeploymentFunc        func(context.Context, string, string) (*sevalla.Response, error)
+	AddCustomDomainFunc         func(context.Context, string, string) (*sevalla.Response, error)
+	RemoveCustomDomainFunc      func(context.Context, string, string) (*sevalla.Response, error)
+	UpdateCDNSettingsFunc       func(context.Context, string, bool) (*sevalla.Response, error)
+	GetUsageFunc                func(context.Context, string, string) (*sevalla.Usage, *sevalla.Response, error)
+	SetEnvironmentVariablesFunc func(context.Context, string, map[string]string) (*sevalla.Response, error)
+	GetEnvironmentVariablesFunc func(context.Context, string) (map[string]sevalla.Secret, *sevalla.Response, error)
+	RollbackFunc                func(context.Context, string, string) (*sevalla.Deployment, *sevalla.Response, error)
+	WaitForStateFunc            func(context.Context, string, sevalla.ApplicationState, *sevalla.WaitOptions[*sevalla.Application]) (*sevalla.Application, error)
+}
+
+// List calls ListFunc
+func (m *Applications) List(ctx context.Context, opts *sevalla.ListOptions) ([]*sevalla.Application, *sevalla.Response, error) {
+	if m.ListFunc == nil {
+		return nil, nil, notImplemented("Applications.List")
+	}
+	return m.ListFunc(ctx, opts)
+}
+
+// ListIter calls ListIterFunc
+func (m *Applications) ListIter(ctx context.Context, opts *sevalla.ListOptions) iter.Seq2[*sevalla.Application, error] {
+	if m.ListIterFunc == nil {
+		return func(yield func(*sevalla.Application, error) bool) {
+			yield(nil, notImplemented("Applications.ListIter"))
+		}
+	}
+	return m.ListIterFunc(ctx, opts)
+}
+
+// ListAll calls ListAllFunc
+func (m *Applications) ListAll(ctx context.Context, opts *sevalla.ListOptions) ([]*sevalla.Application, error) {
+	if m.ListAllFunc == nil {
+		return nil, notImplemented("Applications.ListAll")
+	}
+	return m.ListAllFunc(ctx, opts)
+}
+
+// Get calls GetFunc
+func (m *Applications) Get(ctx context.Context, id string) (*sevalla.Application, *sevalla.Response, error) {
+	if m.GetFunc == nil {
+		return nil, nil, notImplemented("Applications.Get")
+	}
+	return m.GetFunc(ctx, id)
+}
+
+// Create calls CreateFunc
+func (m *Applications) Create(ctx context.Context, createReq *sevalla.CreateApplicationRequest) (*sevalla.Application, *sevalla.Response, error) {
+	if m.CreateFunc == nil {
+		return nil, nil, notImplemented("Applications.Create")
+	}
+	return m.CreateFunc(ctx, createReq)
+}
+
+// Update calls UpdateFunc
+func (m *Applications) Update(ctx context.Context, id string, updateReq *sevalla.UpdateApplicationRequest) (*sevalla.Application, *sevalla.Response, error) {
+	if m.UpdateFunc == nil {
+		return nil, nil, notImplemented("Applications.Update")
+	}
+	return m.UpdateFunc(ctx, id, updateReq)
+}
+
+// Modify calls ModifyFunc
+func (m *Applications) Modify(ctx context.Context, id string, mutate func(app *sevalla.Application) (*sevalla.UpdateApplicationRequest, error)) (*sevalla.Application, *sevalla.Response, error) {
+	if m.ModifyFunc == nil {
+		return nil, nil, notImplemented("Applications.Modify")
+	}
+	return m.ModifyFunc(ctx, id, mutate)
+}
+
+// Delete calls DeleteFunc
+func (m *Applications) Delete(ctx context.Context, id string) (*sevalla.Response, error) {
+	if m.DeleteFunc == nil {
+		return nil, notImplemented("Applications.Delete")
+	}
+	return m.DeleteFunc(ctx, id)
+}
+
+// Scale calls ScaleFunc
+func (m *Applications) Scale(ctx context.Context, id string, scaleReq *sevalla.ScaleApplicationRequest) (*sevalla.Application, *sevalla.Response, error) {
+	if m.ScaleFunc == nil {
+		return nil, nil, notImplemented("Applications.Scale")
+	}
+	return m.ScaleFunc(ctx, id, scaleReq)
+}
+
+// Deploy calls DeployFunc
+func (m *Applications) Deploy(ctx context.Context, id string) (*sevalla.Deployment, *sevalla.Response, error) {
+	if m.DeployFunc == nil {
+		return nil, nil, notImplemented("Applications.Deploy")
+	}
+	return m.DeployFunc(ctx, id)
+}
+
+// Restart calls RestartFunc
+func (m *Applications) Restart(ctx context.Context, id string) (*sevalla.Response, error) {
+	if m.RestartFunc == nil {
+		return nil, notImplemented("Applications.Restart")
+	}
+	return m.RestartFunc(ctx, id)
+}
+
+// Stop calls StopFunc
+func (m *Applications) Stop(ctx context.Context, id string) (*sevalla.Response, error) {
+	if m.StopFunc == nil {
+		return nil, notImplemented("Applications.Stop")
+	}
+	return m.StopFunc(ctx, id)
+}
+
+// Start calls StartFunc
+func (m *Applications) Start(ctx context.Context, id string) (*sevalla.Response, error) {
+	if m.StartFunc == nil {
+		return nil, notImplemented("Applications.Start")
+	}
+	return m.StartFunc(ctx, id)
+}
+
+// GetLogs calls GetLogsFunc
+func (m *Applications) GetLogs(ctx context.Context, id string, lines int) (string, *sevalla.Response, error) {
+	if m.GetLogsFunc == nil {
+		return "", nil, notImplemented("Applications.GetLogs")
+	}
+	return m.GetLogsFunc(ctx, id, lines)
+}
+
+// TailLogs calls TailLogsFunc
+func (m *Applications) TailLogs(ctx context.Context, id string, opts *sevalla.TailLogsOptions) (*sevalla.LogStream, error) {
+	if m.TailLogsFunc == nil {
+		return nil, notImplemented("Applications.TailLogs")
+	}
+	return m.TailLogsFunc(ctx, id, opts)
+}
+
+// ListDeployments calls ListDeploymentsFunc
+func (m *Applications) ListDeployments(ctx context.Context, id string, opts *sevalla.ListOptions) ([]*sevalla.Deployment, *sevalla.Response, error) {
+	if m.ListDeploymentsFunc == nil {
+		return nil, nil, notImplemented("Applications.ListDeployments")
+	}
+	return m.ListDeploymentsFunc(ctx, id, opts)
+}
+
+// ListDeploymentsIter calls ListDeploymentsIterFunc
+func (m *Applications) ListDeploymentsIter(ctx context.Context, id string, opts *sevalla.ListOptions) iter.Seq2[*sevalla.Deployment, error] {
+	if m.ListDeploymentsIterFunc == nil {
+		return func(yield func(*sevalla.Deployment, error) bool) {
+			yield(nil, notImplemented("Applications.ListDeploymentsIter"))
+		}
+	}
+	return m.ListDeploymentsIterFunc(ctx, id, opts)
+}
+
+// ListAllDeployments calls ListAllDeploymentsFunc
+func (m *Applications) ListAllDeployments(ctx context.Context, id string, opts *sevalla.ListOptions) ([]*sevalla.Deployment, error) {
+	if m.ListAllDeploymentsFunc == nil {
+		return nil, notImplemented("Applications.ListAllDeployments")
+	}
+	return m.ListAllDeploymentsFunc(ctx, id, opts)
+}
+
+// GetDeployment calls GetDeploymentFunc
+func (m *Applications) GetDeployment(ctx context.Context, appID string, deploymentID string) (*sevalla.Deployment, *sevalla.Response, error) {
+	if m.GetDeploymentFunc == nil {
+		return nil, nil, notImplemented("Applications.GetDeployment")
+	}
+	return m.GetDeploymentFunc(ctx, appID, deploymentID)
+}
+
+// CancelDeployment calls CancelDeploymentFunc
+func (m *Applications) CancelDeployment(ctx context.Context, appID string, deploymentID string) (*sevalla.Response, error) {
+	if m.CancelDeploymentFunc == nil {
+		return nil, notImplemented("Applications.CancelDeployment")
+	}
+	return m.CancelDeploymentFunc(ctx, appID, deploymentID)
+}
+
+// AddCustomDomain calls AddCustomDomainFunc
+func (m *Applications) AddCustomDomain(ctx context.Context, id string, domain string) (*sevalla.Response, error) {
+	if m.AddCustomDomainFunc == nil {
+		return nil, notImplemented("Applications.AddCustomDomain")
+	}
+	return m.AddCustomDomainFunc(ctx, id, domain)
+}
+
+// RemoveCustomDomain calls RemoveCustomDomainFunc
+func (m *Applications) RemoveCustomDomain(ctx context.Context, id string, domain string) (*sevalla.Response, error) {
+	if m.RemoveCustomDomainFunc == nil {
+		return nil, notImplemented("Applications.RemoveCustomDomain")
+	}
+	return m.RemoveCustomDomainFunc(ctx, id, domain)
+}
+
+// UpdateCDNSettings calls UpdateCDNSettingsFunc
+func (m *Applications) UpdateCDNSettings(ctx context.Context, id string, enabled bool) (*sevalla.Response, error) {
+	if m.UpdateCDNSettingsFunc == nil {
+		return nil, notImplemented("Applications.UpdateCDNSettings")
+	}
+	return m.UpdateCDNSettingsFunc(ctx, id, enabled)
+}
+
+// GetUsage calls GetUsageFunc
+func (m *Applications) GetUsage(ctx context.Context, id string, period string) (*sevalla.Usage, *sevalla.Response, error) {
+	if m.GetUsageFunc == nil {
+		return nil, nil, notImplemented("Applications.GetUsage")
+	}
+	return m.GetUsageFunc(ctx, id, period)
+}
+
+// SetEnvironmentVariables calls SetEnvironmentVariablesFunc
+func (m *Applications) SetEnvironmentVariables(ctx context.Context, id string, vars map[string]string) (*sevalla.Response, error) {
+	if m.SetEnvironmentVariablesFunc == nil {
+		return nil, notImplemented("Applications.SetEnvironmentVariables")
+	}
+	return m.SetEnvironmentVariablesFunc(ctx, id, vars)
+}
+
+// GetEnvironmentVariables calls GetEnvironmentVariablesFunc
+func (m *Applications) GetEnvironmentVariables(ctx context.Context, id string) (map[string]sevalla.Secret, *sevalla.Response, error) {
+	if m.GetEnvironmentVariablesFunc == nil {
+		return nil, nil, notImplemented("Applications.GetEnvironmentVariables")
+	}
+	return m.GetEnvironmentVariablesFunc(ctx, id)
+}
+
+// Rollback calls RollbackFunc
+func (m *Applications) Rollback(ctx context.Context, appID string, deploymentID string) (*sevalla.Deployment, *sevalla.Response, error) {
+	if m.RollbackFunc == nil {
+		return nil, nil, notImplemented("Applications.Rollback")
+	}
+	return m.RollbackFunc(ctx, appID, deploymentID)
+}
+
+// WaitForState calls WaitForStateFunc
+func (m *Applications) WaitForState(ctx context.Context, id string, state sevalla.ApplicationState, opts *sevalla.WaitOptions[*sevalla.Application]) (*sevalla.Application, error) {
+	if m.WaitForStateFunc == nil {
+		return nil, notImplemented("Applications.WaitForState")
+	}
+	return m.WaitForStateFunc(ctx, id, state, opts)
+}
+
+// Databases is a mock implementation of sevalla.DatabasesAPI
+type Databases struct {
+	ListFunc                func(context.Context, *sevalla.ListOptions) ([]*sevalla.Database, *sevalla.Response, error)
+	ListIterFunc            func(context.Context, *sevalla.ListOptions) iter.Seq2[*sevalla.Database, error]
+	ListAllFunc             func(context.Context, *sevalla.ListOptions) ([]*sevalla.Database, error)
+	GetFunc                 func(context.Context, string) (*sevalla.Database, *sevalla.Response, error)
+	CreateFunc              func(context.Context, *sevalla.CreateDatabaseRequest) (*sevalla.Database, *sevalla.Response, error)
+	UpdateFunc              func(context.Context, string, *sevalla.UpdateDatabaseRequest) (*sevalla.Database, *sevalla.Response, error)
+	ModifyFunc              func(context.Context, string, func(*sevalla.Database) (*sevalla.UpdateDatabaseRequest, error)) (*sevalla.Database, *sevalla.Response, error)
+	DeleteFunc              func(context.Context, string) (*sevalla.Response, error)
+	GetCredentialsFunc      func(context.Context, string) (*sevalla.Database, *sevalla.Response, error)
+	ResetPasswordFunc       func(context.Context, string) (*sevalla.Database, *sevalla.Response, error)
+	ListBackupsFunc         func(context.Context, string, *sevalla.ListOptions) ([]*sevalla.Backup, *sevalla.Response, error)
+	ListBackupsIterFunc     func(context.Context, string, *sevalla.ListOptions) iter.Seq2[*sevalla.Backup, error]
+	ListAllBackupsFunc      func(context.Context, string, *sevalla.ListOptions) ([]*sevalla.Backup, error)
+	CreateBackupFunc        func(context.Context, string, *sevalla.CreateBackupRequest) (*sevalla.Backup, *sevalla.Response, error)
+	GetBackupFunc           func(context.Context, string, string) (*sevalla.Backup, *sevalla.Response, error)
+	DeleteBackupFunc        func(context.Context, string, string) (*sevalla.Response, error)
+	RestoreFromBackupFunc   func(context.Context, string, *sevalla.RestoreBackupRequest) (*sevalla.Response, error)
+	GetUsageFunc            func(context.Context, string, string) (*sevalla.Usage, *sevalla.Response, error)
+	EnablePublicAccessFunc  func(context.Context, string) (*sevalla.Database, *sevalla.Response, error)
+	DisablePublicAccessFunc func(context.Context, string) (*sevalla.Database, *sevalla.Response, error)
+	WaitUntilReadyFunc      func(context.Context, string, *sevalla.WaitOptions[*sevalla.Database]) (*sevalla.Database, error)
+	WaitForBackupFunc       func(context.Context, string, string, *sevalla.WaitOptions[*sevalla.Backup]) (*sevalla.Backup, error)
+}
+
+// List calls ListFunc
+func (m *Databases) List(ctx context.Context, opts *sevalla.ListOptions) ([]*sevalla.Database, *sevalla.Response, error) {
+	if m.ListFunc == nil {
+		return nil, nil, notImplemented("Databases.List")
+	}
+	return m.ListFunc(ctx, opts)
+}
+
+// ListIter calls ListIterFunc
+func (m *Databases) ListIter(ctx context.Context, opts *sevalla.ListOptions) iter.Seq2[*sevalla.Database, error] {
+	if m.ListIterFunc == nil {
+		return func(yield func(*sevalla.Database, error) bool) {
+			yield(nil, notImplemented("Databases.ListIter"))
+		}
+	}
+	return m.ListIterFunc(ctx, opts)
+}
+
+// ListAll calls ListAllFunc
+func (m *Databases) ListAll(ctx context.Context, opts *sevalla.ListOptions) ([]*sevalla.Database, error) {
+	if m.ListAllFunc == nil {
+		return nil, notImplemented("Databases.ListAll")
+	}
+	return m.ListAllFunc(ctx, opts)
+}
+
+// Get calls GetFunc
+func (m *Databases) Get(ctx context.Context, id string) (*sevalla.Database, *sevalla.Response, error) {
+	if m.GetFunc == nil {
+		return nil, nil, notImplemented("Databases.Get")
+	}
+	return m.GetFunc(ctx, id)
+}
+
+// Create calls CreateFunc
+func (m *Databases) Create(ctx context.Context, createReq *sevalla.CreateDatabaseRequest) (*sevalla.Database, *sevalla.Response, error) {
+	if m.CreateFunc == nil {
+		return nil, nil, notImplemented("Databases.Create")
+	}
+	return m.CreateFunc(ctx, createReq)
+}
+
+// Update calls UpdateFunc
+func (m *Databases) Update(ctx context.Context, id string, updateReq *sevalla.UpdateDatabaseRequest) (*sevalla.Database, *sevalla.Response, error) {
+	if m.UpdateFunc == nil {
+		return nil, nil, notImplemented("Databases.Update")
+	}
+	return m.UpdateFunc(ctx, id, updateReq)
+}
+
+// Modify calls ModifyFunc
+func (m *Databases) Modify(ctx context.Context, id string, mutate func(database *sevalla.Database) (*sevalla.UpdateDatabaseRequest, error)) (*sevalla.Database, *sevalla.Response, error) {
+	if m.ModifyFunc == nil {
+		return nil, nil, notImplemented("Databases.Modify")
+	}
+	return m.ModifyFunc(ctx, id, mutate)
+}
+
+// Delete calls DeleteFunc
+func (m *Databases) Delete(ctx context.Context, id string) (*sevalla.Response, error) {
+	if m.DeleteFunc == nil {
+		return nil, notImplemented("Databases.Delete")
+	}
+	return m.DeleteFunc(ctx, id)
+}
+
+// GetCredentials calls GetCredentialsFunc
+func (m *Databases) GetCredentials(ctx context.Context, id string) (*sevalla.Database, *sevalla.Response, error) {
+	if m.GetCredentialsFunc == nil {
+		return nil, nil, notImplemented("Databases.GetCredentials")
+	}
+	return m.GetCredentialsFunc(ctx, id)
+}
+
+// ResetPassword calls ResetPasswordFunc
+func (m *Databases) ResetPassword(ctx context.Context, id string) (*sevalla.Database, *sevalla.Response, error) {
+	if m.ResetPasswordFunc == nil {
+		return nil, nil, notImplemented("Databases.ResetPassword")
+	}
+	return m.ResetPasswordFunc(ctx, id)
+}
+
+// ListBackups calls ListBackupsFunc
+func (m *Databases) ListBackups(ctx context.Context, id string, opts *sevalla.ListOptions) ([]*sevalla.Backup, *sevalla.Response, error) {
+	if m.ListBackupsFunc == nil {
+		return nil, nil, notImplemented("Databases.ListBackups")
+	}
+	return m.ListBackupsFunc(ctx, id, opts)
+}
+
+// ListBackupsIter calls ListBackupsIterFunc
+func (m *Databases) ListBackupsIter(ctx context.Context, id string, opts *sevalla.ListOptions) iter.Seq2[*sevalla.Backup, error] {
+	if m.ListBackupsIterFunc == nil {
+		return func(yield func(*sevalla.Backup, error) bool) {
+			yield(nil, notImplemented("Databases.ListBackupsIter"))
+		}
+	}
+	return m.ListBackupsIterFunc(ctx, id, opts)
+}
+
+// ListAllBackups calls ListAllBackupsFunc
+func (m *Databases) ListAllBackups(ctx context.Context, id string, opts *sevalla.ListOptions) ([]*sevalla.Backup, error) {
+	if m.ListAllBackupsFunc == nil {
+		return nil, notImplemented("Databases.ListAllBackups")
+	}
+	return m.ListAllBackupsFunc(ctx, id, opts)
+}
+
+// CreateBackup calls CreateBackupFunc
+func (m *Databases) CreateBackup(ctx context.Context, id string, backupReq *sevalla.CreateBackupRequest) (*sevalla.Backup, *sevalla.Response, error) {
+	if m.CreateBackupFunc == nil {
+		return nil, nil, notImplemented("Databases.CreateBackup")
+	}
+	return m.CreateBackupFunc(ctx, id, backupReq)
+}
+
+// GetBackup calls GetBackupFunc
+func (m *Databases) GetBackup(ctx context.Context, dbID string, backupID string) (*sevalla.Backup, *sevalla.Response, error) {
+	if m.GetBackupFunc == nil {
+		return nil, nil, notImplemented("Databases.GetBackup")
+	}
+	return m.GetBackupFunc(ctx, dbID, backupID)
+}
+
+// DeleteBackup calls DeleteBackupFunc
+func (m *Databases) DeleteBackup(ctx context.Context, dbID string, backupID string) (*sevalla.Response, error) {
+	if m.DeleteBackupFunc == nil {
+		return nil, notImplemented("Databases.DeleteBackup")
+	}
+	return m.DeleteBackupFunc(ctx, dbID, backupID)
+}
+
+// RestoreFromBackup calls RestoreFromBackupFunc
+func (m *Databases) RestoreFromBackup(ctx context.Context, id string, restoreReq *sevalla.RestoreBackupRequest) (*sevalla.Response, error) {
+	if m.RestoreFromBackupFunc == nil {
+		return nil, notImplemented("Databases.RestoreFromBackup")
+	}
+	return m.RestoreFromBackupFunc(ctx, id, restoreReq)
+}
+
+// GetUsage calls GetUsageFunc
+func (m *Databases) GetUsage(ctx context.Context, id string, period string) (*sevalla.Usage, *sevalla.Response, error) {
+	if m.GetUsageFunc == nil {
+		return nil, nil, notImplemented("Databases.GetUsage")
+	}
+	return m.GetUsageFunc(ctx, id, period)
+}
+
+// EnablePublicAccess calls EnablePublicAccessFunc
+func (m *Databases) EnablePublicAccess(ctx context.Context, id string) (*sevalla.Database, *sevalla.Response, error) {
+	if m.EnablePublicAccessFunc == nil {
+		return nil, nil, notImplemented("Databases.EnablePublicAccess")
+	}
+	return m.EnablePublicAccessFunc(ctx, id)
+}
+
+// DisablePublicAccess calls DisablePublicAccessFunc
+func (m *Databases) DisablePublicAccess(ctx context.Context, id string) (*sevalla.Database, *sevalla.Response, error) {
+	if m.DisablePublicAccessFunc == nil {
+		return nil, nil, notImplemented("Databases.DisablePublicAccess")
+	}
+	return m.DisablePublicAccessFunc(ctx, id)
+}
+
+// WaitUntilReady calls WaitUntilReadyFunc
+func (m *Databases) WaitUntilReady(ctx context.Context, id string, opts *sevalla.WaitOptions[*sevalla.Database]) (*sevalla.Database, error) {
+	if m.WaitUntilReadyFunc == nil {
+		return nil, notImplemented("Databases.WaitUntilReady")
+	}
+	return m.WaitUntilReadyFunc(ctx, id, opts)
+}
+
+// WaitForBackup calls WaitForBackupFunc
+func (m *Databases) WaitForBackup(ctx context.Context, dbID string, backupID string, opts *sevalla.WaitOptions[*sevalla.Backup]) (*sevalla.Backup, error) {
+	if m.WaitForBackupFunc == nil {
+		return nil, notImplemented("Databases.WaitForBackup")
+	}
+	return m.WaitForBackupFunc(ctx, dbID, backupID, opts)
+}
+
+// StaticSites is a mock implementation of sevalla.StaticSitesAPI
+type StaticSites struct {
+	ListFunc     func(context.Context, *sevalla.ListOptions) ([]*sevalla.StaticSite, *sevalla.Response, error)
+	ListIterFunc func(context.Context, *sevalla.ListOptions) iter.Seq2[*sevalla.StaticSite, error]
+	ListAllFunc  func(context.Context, *sevalla.ListOptions) ([]*sevalla.StaticSite, error)
+	GetFunc      func(context.Context, string) (*sevalla.StaticSite, *sevalla.Response, error)
+	CreateFunc   func(context.Context, *sevalla.CreateStaticSiteRequest) (*sevalla.StaticSite, *sevalla.Response, error)
+	DeleteFunc   func(context.Context, string) (*sevalla.Response, error)
+	DeployFunc   func(context.Context, string) (*sevalla.Deployment, *sevalla.Response, error)
+}
+
+// List calls ListFunc
+func (m *StaticSites) List(ctx context.Context, opts *sevalla.ListOptions) ([]*sevalla.StaticSite, *sevalla.Response, error) {
+	if m.ListFunc == nil {
+		return nil, nil, notImplemented("StaticSites.List")
+	}
+	return m.ListFunc(ctx, opts)
+}
+
+// ListIter calls ListIterFunc
+func (m *StaticSites) ListIter(ctx context.Context, opts *sevalla.ListOptions) iter.Seq2[*sevalla.StaticSite, error] {
+	if m.ListIterFunc == nil {
+		return func(yield func(*sevalla.StaticSite, error) bool) {
+			yield(nil, notImplemented("StaticSites.ListIter"))
+		}
+	}
+	return m.ListIterFunc(ctx, opts)
+}
+
+// ListAll calls ListAllFunc
+func (m *StaticSites) ListAll(ctx context.Context, opts *sevalla.ListOptions) ([]*sevalla.StaticSite, error) {
+	if m.ListAllFunc == nil {
+		return nil, notImplemented("StaticSites.ListAll")
+	}
+	return m.ListAllFunc(ctx, opts)
+}
+
+// Get calls GetFunc
+func (m *StaticSites) Get(ctx context.Context, id string) (*sevalla.StaticSite, *sevalla.Response, error) {
+	if m.GetFunc == nil {
+		return nil, nil, notImplemented("StaticSites.Get")
+	}
+	return m.GetFunc(ctx, id)
+}
+
+// Create calls CreateFunc
+func (m *StaticSites) Create(ctx context.Context, createReq *sevalla.CreateStaticSiteRequest) (*sevalla.StaticSite, *sevalla.Response, error) {
+	if m.CreateFunc == nil {
+		return nil, nil, notImplemented("StaticSites.Create")
+	}
+	return m.CreateFunc(ctx, createReq)
+}
+
+// Delete calls DeleteFunc
+func (m *StaticSites) Delete(ctx context.Context, id string) (*sevalla.Response, error) {
+	if m.DeleteFunc == nil {
+		return nil, notImplemented("StaticSites.Delete")
+	}
+	return m.DeleteFunc(ctx, id)
+}
+
+// Deploy calls DeployFunc
+func (m *StaticSites) Deploy(ctx context.Context, id string) (*sevalla.Deployment, *sevalla.Response, error) {
+	if m.DeployFunc == nil {
+		return nil, nil, notImplemented("StaticSites.Deploy")
+	}
+	return m.DeployFunc(ctx, id)
+}
+
+// Deployments is a mock implementation of sevalla.DeploymentsAPI
+type Deployments struct {
+	GetFunc               func(context.Context, string) (*sevalla.Deployment, *sevalla.Response, error)
+	ListFunc              func(context.Context, *sevalla.ListOptions) ([]*sevalla.Deployment, *sevalla.Response, error)
+	ListIterFunc          func(context.Context, *sevalla.ListOptions) iter.Seq2[*sevalla.Deployment, error]
+	ListAllFunc           func(context.Context, *sevalla.ListOptions) ([]*sevalla.Deployment, error)
+	GetLogsFunc           func(context.Context, string) (string, *sevalla.Response, error)
+	TailLogsFunc          func(context.Context, string, *sevalla.TailLogsOptions) (*sevalla.LogStream, error)
+	CancelFunc            func(context.Context, string) (*sevalla.Response, error)
+	WaitForDeploymentFunc func(context.Context, string, *sevalla.WaitOptions[*sevalla.Deployment]) (*sevalla.Deployment, error)
+}
+
+// Get calls GetFunc
+func (m *Deployments) Get(ctx context.Context, id string) (*sevalla.Deployment, *sevalla.Response, error) {
+	if m.GetFunc == nil {
+		return nil, nil, notImplemented("Deployments.Get")
+	}
+	return m.GetFunc(ctx, id)
+}
+
+// List calls ListFunc
+func (m *Deployments) List(ctx context.Context, opts *sevalla.ListOptions) ([]*sevalla.Deployment, *sevalla.Response, error) {
+	if m.ListFunc == nil {
+		return nil, nil, notImplemented("Deployments.List")
+	}
+	return m.ListFunc(ctx, opts)
+}
+
+// ListIter calls ListIterFunc
+func (m *Deployments) ListIter(ctx context.Context, opts *sevalla.ListOptions) iter.Seq2[*sevalla.Deployment, error] {
+	if m.ListIterFunc == nil {
+		return func(yield func(*sevalla.Deployment, error) bool) {
+			yield(nil, notImplemented("Deployments.ListIter"))
+		}
+	}
+	return m.ListIterFunc(ctx, opts)
+}
+
+// ListAll calls ListAllFunc
+func (m *Deployments) ListAll(ctx context.Context, opts *sevalla.ListOptions) ([]*sevalla.Deployment, error) {
+	if m.ListAllFunc == nil {
+		return nil, notImplemented("Deployments.ListAll")
+	}
+	return m.ListAllFunc(ctx, opts)
+}
+
+// GetLogs calls GetLogsFunc
+func (m *Deployments) GetLogs(ctx context.Context, id string) (string, *sevalla.Response, error) {
+	if m.GetLogsFunc == nil {
+		return "", nil, notImplemented("Deployments.GetLogs")
+	}
+	return m.GetLogsFunc(ctx, id)
+}
+
+// TailLogs calls TailLogsFunc
+func (m *Deployments) TailLogs(ctx context.Context, id string, opts *sevalla.TailLogsOptions) (*sevalla.LogStream, error) {
+	if m.TailLogsFunc == nil {
+		return nil, notImplemented("Deployments.TailLogs")
+	}
+	return m.TailLogsFunc(ctx, id, opts)
+}
+
+// Cancel calls CancelFunc
+func (m *Deployments) Cancel(ctx context.Context, id string) (*sevalla.Response, error) {
+	if m.CancelFunc == nil {
+		return nil, notImplemented("Deployments.Cancel")
+	}
+	return m.CancelFunc(ctx, id)
+}
+
+// WaitForDeployment calls WaitForDeploymentFunc
+func (m *Deployments) WaitForDeployment(ctx context.Context, id string, opts *sevalla.WaitOptions[*sevalla.Deployment]) (*sevalla.Deployment, error) {
+	if m.WaitForDeploymentFunc == nil {
+		return nil, notImplemented("Deployments.WaitForDeployment")
+	}
+	return m.WaitForDeploymentFunc(ctx, id, opts)
+}
+
+// Pipelines is a mock implementation of sevalla.PipelinesAPI
+type Pipelines struct {
+	ListFunc         func(context.Context, *sevalla.ListOptions) ([]*sevalla.Pipeline, *sevalla.Response, error)
+	ListIterFunc     func(context.Context, *sevalla.ListOptions) iter.Seq2[*sevalla.Pipeline, error]
+	ListAllFunc      func(context.Context, *sevalla.ListOptions) ([]*sevalla.Pipeline, error)
+	GetFunc          func(context.Context, string) (*sevalla.Pipeline, *sevalla.Response, error)
+	CreateFunc       func(context.Context, *sevalla.CreatePipelineRequest) (*sevalla.Pipeline, *sevalla.Response, error)
+	UpdateFunc       func(context.Context, string, *sevalla.UpdatePipelineRequest) (*sevalla.Pipeline, *sevalla.Response, error)
+	ModifyFunc       func(context.Context, string, func(*sevalla.Pipeline) (*sevalla.UpdatePipelineRequest, error)) (*sevalla.Pipeline, *sevalla.Response, error)
+	DeleteFunc       func(context.Context, string) (*sevalla.Response, error)
+	RunFunc          func(context.Context, string) (*sevalla.PipelineRun, *sevalla.Response, error)
+	ListRunsFunc     func(context.Context, string, *sevalla.ListOptions) ([]*sevalla.PipelineRun, *sevalla.Response, error)
+	ListRunsIterFunc func(context.Context, string, *sevalla.ListOptions) iter.Seq2[*sevalla.PipelineRun, error]
+	ListAllRunsFunc  func(context.Context, string, *sevalla.ListOptions) ([]*sevalla.PipelineRun, error)
+	GetRunFunc       func(context.Context, string, string) (*sevalla.PipelineRun, *sevalla.Response, error)
+	CancelRunFunc    func(context.Context, string, string) (*sevalla.Response, error)
+	GetRunLogsFunc   func(context.Context, string, string) (string, *sevalla.Response, error)
+	RetryRunFunc     func(context.Context, string, string) (*sevalla.PipelineRun, *sevalla.Response, error)
+	WaitForRunFunc   func(context.Context, string, string, *sevalla.WaitOptions[*sevalla.PipelineRun]) (*sevalla.PipelineRun, error)
+}
+
+// List calls ListFunc
+func (m *Pipelines) List(ctx context.Context, opts *sevalla.ListOptions) ([]*sevalla.Pipeline, *sevalla.Response, error) {
+	if m.ListFunc == nil {
+		return nil, nil, notImplemented("Pipelines.List")
+	}
+	return m.ListFunc(ctx, opts)
+}
+
+// ListIter calls ListIterFunc
+func (m *Pipelines) ListIter(ctx context.Context, opts *sevalla.ListOptions) iter.Seq2[*sevalla.Pipeline, error] {
+	if m.ListIterFunc == nil {
+		return func(yield func(*sevalla.Pipeline, error) bool) {
+			yield(nil, notImplemented("Pipelines.ListIter"))
+		}
+	}
+	return m.ListIterFunc(ctx, opts)
+}
+
+// ListAll calls ListAllFunc
+func (m *Pipelines) ListAll(ctx context.Context, opts *sevalla.ListOptions) ([]*sevalla.Pipeline, error) {
+	if m.ListAllFunc == nil {
+		return nil, notImplemented("Pipelines.ListAll")
+	}
+	return m.ListAllFunc(ctx, opts)
+}
+
+// Get calls GetFunc
+func (m *Pipelines) Get(ctx context.Context, id string) (*sevalla.Pipeline, *sevalla.Response, error) {
+	if m.GetFunc == nil {
+		return nil, nil, notImplemented("Pipelines.Get")
+	}
+	return m.GetFunc(ctx, id)
+}
+
+// Create calls CreateFunc
+func (m *Pipelines) Create(ctx context.Context, createReq *sevalla.CreatePipelineRequest) (*sevalla.Pipeline, *sevalla.Response, error) {
+	if m.CreateFunc == nil {
+		return nil, nil, notImplemented("Pipelines.Create")
+	}
+	return m.CreateFunc(ctx, createReq)
+}
+
+// Update calls UpdateFunc
+func (m *Pipelines) Update(ctx context.Context, id string, updateReq *sevalla.UpdatePipelineRequest) (*sevalla.Pipeline, *sevalla.Response, error) {
+	if m.UpdateFunc == nil {
+		return nil, nil, notImplemented("Pipelines.Update")
+	}
+	return m.UpdateFunc(ctx, id, updateReq)
+}
+
+// Modify calls ModifyFunc
+func (m *Pipelines) Modify(ctx context.Context, id string, mutate func(pipeline *sevalla.Pipeline) (*sevalla.UpdatePipelineRequest, error)) (*sevalla.Pipeline, *sevalla.Response, error) {
+	if m.ModifyFunc == nil {
+		return nil, nil, notImplemented("Pipelines.Modify")
+	}
+	return m.ModifyFunc(ctx, id, mutate)
+}
+
+// Delete calls DeleteFunc
+func (m *Pipelines) Delete(ctx context.Context, id string) (*sevalla.Response, error) {
+	if m.DeleteFunc == nil {
+		return nil, notImplemented("Pipelines.Delete")
+	}
+	return m.DeleteFunc(ctx, id)
+}
+
+// Run calls RunFunc
+func (m *Pipelines) Run(ctx context.Context, id string) (*sevalla.PipelineRun, *sevalla.Response, error) {
+	if m.RunFunc == nil {
+		return nil, nil, notImplemented("Pipelines.Run")
+	}
+	return m.RunFunc(ctx, id)
+}
+
+// ListRuns calls ListRunsFunc
+func (m *Pipelines) ListRuns(ctx context.Context, pipelineID string, opts *sevalla.ListOptions) ([]*sevalla.PipelineRun, *sevalla.Response, error) {
+	if m.ListRunsFunc == nil {
+		return nil, nil, notImplemented("Pipelines.ListRuns")
+	}
+	return m.ListRunsFunc(ctx, pipelineID, opts)
+}
+
+// ListRunsIter calls ListRunsIterFunc
+func (m *Pipelines) ListRunsIter(ctx context.Context, pipelineID string, opts *sevalla.ListOptions) iter.Seq2[*sevalla.PipelineRun, error] {
+	if m.ListRunsIterFunc == nil {
+		return func(yield func(*sevalla.PipelineRun, error) bool) {
+			yield(nil, notImplemented("Pipelines.ListRunsIter"))
+		}
+	}
+	return m.ListRunsIterFunc(ctx, pipelineID, opts)
+}
+
+// ListAllRuns calls ListAllRunsFunc
+func (m *Pipelines) ListAllRuns(ctx context.Context, pipelineID string, opts *sevalla.ListOptions) ([]*sevalla.PipelineRun, error) {
+	if m.ListAllRunsFunc == nil {
+		return nil, notImplemented("Pipelines.ListAllRuns")
+	}
+	return m.ListAllRunsFunc(ctx, pipelineID, opts)
+}
+
+// GetRun calls GetRunFunc
+func (m *Pipelines) GetRun(ctx context.Context, pipelineID string, runID string) (*sevalla.PipelineRun, *sevalla.Response, error) {
+	if m.GetRunFunc == nil {
+		return nil, nil, notImplemented("Pipelines.GetRun")
+	}
+	return m.GetRunFunc(ctx, pipelineID, runID)
+}
+
+// CancelRun calls CancelRunFunc
+func (m *Pipelines) CancelRun(ctx context.Context, pipelineID string, runID string) (*sevalla.Response, error) {
+	if m.CancelRunFunc == nil {
+		return nil, notImplemented("Pipelines.CancelRun")
+	}
+	return m.CancelRunFunc(ctx, pipelineID, runID)
+}
+
+// GetRunLogs calls GetRunLogsFunc
+func (m *Pipelines) GetRunLogs(ctx context.Context, pipelineID string, runID string) (string, *sevalla.Response, error) {
+	if m.GetRunLogsFunc == nil {
+		return "", nil, notImplemented("Pipelines.GetRunLogs")
+	}
+	return m.GetRunLogsFunc(ctx, pipelineID, runID)
+}
+
+// RetryRun calls RetryRunFunc
+func (m *Pipelines) RetryRun(ctx context.Context, pipelineID string, runID string) (*sevalla.PipelineRun, *sevalla.Response, error) {
+	if m.RetryRunFunc == nil {
+		return nil, nil, notImplemented("Pipelines.RetryRun")
+	}
+	return m.RetryRunFunc(ctx, pipelineID, runID)
+}
+
+// WaitForRun calls WaitForRunFunc
+func (m *Pipelines) WaitForRun(ctx context.Context, pipelineID string, runID string, opts *sevalla.WaitOptions[*sevalla.PipelineRun]) (*sevalla.PipelineRun, error) {
+	if m.WaitForRunFunc == nil {
+		return nil, notImplemented("Pipelines.WaitForRun")
+	}
+	return m.WaitForRunFunc(ctx, pipelineID, runID, opts)
+}
+
+// Compile-time assertions that the mocks implement the service interfaces
+var (
+	_ sevalla.ApplicationsAPI = (*Applications)(nil)
+	_ sevalla.DatabasesAPI    = (*Databases)(nil)
+	_ sevalla.StaticSitesAPI  = (*StaticSites)(nil)
+	_ sevalla.DeploymentsAPI  = (*Deployments)(nil)
+	_ sevalla.PipelinesAPI    = (*Pipelines)(nil)
+)
@@ -0,0 +1,46 @@
+package mocks
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/juststeveking/sevalla-go"
+)
+
+func TestApplications_DelegatesToFunc(t *testing.T) {
+	var gotID string
+	var apps sevalla.ApplicationsAPI = &Applications{
+		GetFunc: func(ctx context.Context, id string) (*sevalla.Application, *sevalla.Response, error) {
+			gotID = id
+			return &sevalla.Application{ID: id, State: sevalla.StateRunning}, nil, nil
+		},
+	}
+
+	app, _, err := apps.Get(context.Background(), "app-123")
+	if err != nil {
+		t.Fatalf("Get returned error: %v", err)
+	}
+	if gotID != "app-123" {
+		t.Errorf("Expected GetFunc to receive 'app-123', got %s", gotID)
+	}
+	if app.State != sevalla.StateRunning {
+		t.Errorf("Expected state running, got %s", app.State)
+	}
+}
+
+func TestMocks_NotImplemented(t *testing.T) {
+	ctx := context.Background()
+
+	if _, err := (&Deployments{}).Cancel(ctx, "dep-1"); !errors.Is(err, ErrNotImplemented) {
+		t.Errorf("Expected ErrNotImplemented, got %v", err)
+	}
+
+	var iterErr error
+	for _, err := range (&Pipelines{}).ListIter(ctx, nil) {
+		iterErr = err
+	}
+	if !errors.Is(iterErr, ErrNotImplemented) {
+		t.Errorf("Expected ListIter to yield ErrNotImplemented, got %v", iterErr)
+	}
+}
@@ -0,0 +1,21 @@
+module github.com/juststeveking/sevalla-go/otelsevalla
+
+go 1.23.4
+
+require (
+	github.com/juststeveking/sevalla-go v0.2.1-0.20261016064953-b6a089e71da9
+	go.opentelemetry.io/otel v1.31.0
+	go.opentelemetry.io/otel/sdk v1.31.0
+	go.opentelemetry.io/otel/trace v1.31.0
+)
+
+require (
+	github.com/go-logr/logr v1.4.2 // indirect
+	github.com/go-logr/stdr v1.2.2 // indirect
+	github.com/google/go-querystring v1.1.0 // indirect
+	github.com/google/uuid v1.6.0 // indirect
+	go.opentelemetry.io/otel/metric v1.31.0 // indirect
+	golang.org/x/sys v0.26.0 // indirect
+)
+
+replace github.com/juststeveking/sevalla-go => ../
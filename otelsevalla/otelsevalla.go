@@ -0,0 +1,130 @@
+// Package otelsevalla provides OpenTelemetry tracing for the sevalla client.
+//
+//	client := sevalla.NewClient(
+//		sevalla.WithAPIKey(apiKey),
+//		sevalla.WithTracer(otelsevalla.NewTracer()),
+//	)
+package otelsevalla
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/juststeveking/sevalla-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ScopeName is the instrumentation scope name of the tracer
+const ScopeName = "github.com/juststeveking/sevalla-go/otelsevalla"
+
+// Tracer implements sevalla.Tracer with OpenTelemetry
+type Tracer struct {
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+}
+
+// Option is a function that configures a Tracer
+type Option func(*config)
+
+type config struct {
+	provider   trace.TracerProvider
+	propagator propagation.TextMapPropagator
+}
+
+// WithTracerProvider sets the tracer provider, the global provider is used
+// by default
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(c *config) {
+		c.provider = provider
+	}
+}
+
+// WithPropagator sets the propagator used to inject trace context headers,
+// the global propagator is used by default
+func WithPropagator(propagator propagation.TextMapPropagator) Option {
+	return func(c *config) {
+		c.propagator = propagator
+	}
+}
+
+// NewTracer creates a new OpenTelemetry backed Tracer
+func NewTracer(opts ...Option) *Tracer {
+	c := &config{}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if c.provider == nil {
+		c.provider = otel.GetTracerProvider()
+	}
+
+	if c.propagator == nil {
+		c.propagator = otel.GetTextMapPropagator()
+	}
+
+	return &Tracer{
+		tracer:     c.provider.Tracer(ScopeName, trace.WithInstrumentationVersion(sevalla.Version)),
+		propagator: c.propagator,
+	}
+}
+
+// Start starts a client span for a service operation
+func (t *Tracer) Start(ctx context.Context, name string, attrs ...sevalla.Attribute) (context.Context, sevalla.Span) {
+	ctx, span := t.tracer.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(convert(attrs)...),
+	)
+	return ctx, &Span{span: span}
+}
+
+// Inject writes the trace context of ctx into the request headers
+func (t *Tracer) Inject(ctx context.Context, header http.Header) {
+	t.propagator.Inject(ctx, propagation.HeaderCarrier(header))
+}
+
+// Span implements sevalla.Span with an OpenTelemetry span
+type Span struct {
+	span trace.Span
+}
+
+// SetAttributes sets attributes on the span
+func (s *Span) SetAttributes(attrs ...sevalla.Attribute) {
+	s.span.SetAttributes(convert(attrs)...)
+}
+
+// RecordError records the error and marks the span as failed
+func (s *Span) RecordError(err error) {
+	s.span.RecordError(err)
+	s.span.SetStatus(codes.Error, err.Error())
+}
+
+// End ends the span
+func (s *Span) End() {
+	s.span.End()
+}
+
+// convert turns sevalla attributes into OpenTelemetry attributes
+func convert(attrs []sevalla.Attribute) []attribute.KeyValue {
+	kvs := make([]attribute.KeyValue, 0, len(attrs))
+	for _, attr := range attrs {
+		switch v := attr.Value.(type) {
+		case string:
+			kvs = append(kvs, attribute.String(attr.Key, v))
+		case int:
+			kvs = append(kvs, attribute.Int(attr.Key, v))
+		case bool:
+			kvs = append(kvs, attribute.Bool(attr.Key, v))
+		}
+	}
+	return kvs
+}
+
+// Compile-time assertions that the types implement the sevalla interfaces
+var (
+	_ sevalla.Tracer = (*Tracer)(nil)
+	_ sevalla.Span   = (*Span)(nil)
+)
@@ -0,0 +1,89 @@
+package otelsevalla
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/juststeveking/sevalla-go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracer(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	defer func() {
+		_ = provider.Shutdown(context.Background())
+	}()
+
+	var traceparent string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/applications/app-1/deployments", func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("Traceparent")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(&sevalla.Deployment{ID: "dep-1"})
+	})
+	mux.HandleFunc("/applications/missing", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"message":"Not found","code":"not_found"}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := sevalla.NewClient(
+		sevalla.WithBaseURL(server.URL),
+		sevalla.WithTracer(NewTracer(
+			WithTracerProvider(provider),
+			WithPropagator(propagation.TraceContext{}),
+		)),
+	)
+
+	if _, _, err := client.Applications.Deploy(context.Background(), "app-1"); err != nil {
+		t.Fatalf("Applications.Deploy returned error: %v", err)
+	}
+	if _, _, err := client.Applications.Get(context.Background(), "missing"); err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("Expected 2 spans, got %d", len(spans))
+	}
+
+	deploy := spans[0]
+	if deploy.Name != "sevalla.Applications.Deploy" {
+		t.Errorf("Expected span sevalla.Applications.Deploy, got %s", deploy.Name)
+	}
+	if traceparent == "" || traceparent[3:35] != deploy.SpanContext.TraceID().String() {
+		t.Errorf("Expected traceparent header for trace %s, got %q", deploy.SpanContext.TraceID(), traceparent)
+	}
+	assertAttribute(t, deploy.Attributes, attribute.String("sevalla.application.id", "app-1"))
+	assertAttribute(t, deploy.Attributes, attribute.Int("http.response.status_code", http.StatusCreated))
+
+	get := spans[1]
+	if get.Status.Code != codes.Error {
+		t.Errorf("Expected error status, got %v", get.Status.Code)
+	}
+	assertAttribute(t, get.Attributes, attribute.String("sevalla.error.code", "not_found"))
+}
+
+func assertAttribute(t *testing.T, attrs []attribute.KeyValue, want attribute.KeyValue) {
+	t.Helper()
+	for _, attr := range attrs {
+		if attr.Key == want.Key {
+			if attr.Value != want.Value {
+				t.Errorf("Expected %s = %v, got %v", want.Key, want.Value.Emit(), attr.Value.Emit())
+			}
+			return
+		}
+	}
+	t.Errorf("Expected attribute %s, got %v", want.Key, attrs)
+}
@@ -0,0 +1,75 @@
+package sevalla
+
+import (
+	"context"
+	"iter"
+)
+
+// pageFunc fetches a single page of a collection
+type pageFunc[T any] func(ctx context.Context, opts *ListOptions) ([]T, *Response, error)
+
+// paginate returns an iterator over every item of a paginated collection.
+// Pages are requested lazily by following Response.NextPage, starting from
+// opts.Page or page 1, until the last page or opts.MaxItems items have been
+// yielded. A failed request yields the error once and ends the iteration.
+// The iteration is traced as the given operation, with one child span per
+// page.
+func paginate[T any](ctx context.Context, c *Client, operation string, opts *ListOptions, list pageFunc[T], attrs ...Attribute) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		ctx, span := c.startSpan(ctx, operation, attrs...)
+		defer span.End()
+		// Work on a copy so the caller's options are left untouched
+		var pageOpts ListOptions
+		if opts != nil {
+			pageOpts = *opts
+		}
+
+		// The API numbers pages from 1, so a NextPage of 1 after an unset
+		// page points back at the page already read
+		if pageOpts.Page < 1 {
+			pageOpts.Page = 1
+		}
+
+		count := 0
+		for {
+			items, resp, err := list(ctx, &pageOpts)
+			if err != nil {
+				span.RecordError(err)
+				var zero T
+				yield(zero, err)
+				return
+			}
+
+			for _, item := range items {
+				if !yield(item, nil) {
+					return
+				}
+
+				count++
+				if pageOpts.MaxItems > 0 && count >= pageOpts.MaxItems {
+					return
+				}
+			}
+
+			if resp == nil || resp.NextPage == 0 || resp.NextPage <= pageOpts.Page || len(items) == 0 {
+				return
+			}
+
+			pageOpts.Page = resp.NextPage
+		}
+	}
+}
+
+// collect gathers all items of an iterator into a slice, stopping at the
+// first error
+func collect[T any](seq iter.Seq2[T, error]) ([]T, error) {
+	var items []T
+	for item, err := range seq {
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	return items, nil
+}
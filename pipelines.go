@@ -3,6 +3,8 @@ package sevalla
 import (
 	"context"
 	"fmt"
+	"iter"
+	"time"
 )
 
 // PipelinesService handles communication with pipeline-related endpoints
@@ -32,8 +34,70 @@ type UpdatePipelineRequest struct {
 	Metadata    map[string]interface{} `json:"metadata,omitempty"`
 }
 
+// Validate checks the request for errors the API would reject
+func (r *CreatePipelineRequest) Validate() error {
+	if r == nil {
+		return nilRequest()
+	}
+
+	var v validator
+	v.required("Name", "name", r.Name)
+	if len(r.Steps) == 0 {
+		v.add("Steps", "steps", "required", "must have at least one step")
+	}
+	validateSteps(&v, r.Steps)
+	return v.err()
+}
+
+// Validate checks the request for errors the API would reject
+func (r *UpdatePipelineRequest) Validate() error {
+	if r == nil {
+		return nilRequest()
+	}
+
+	var v validator
+	v.notEmpty("Name", "name", r.Name)
+	v.notEmpty("Trigger", "trigger", r.Trigger)
+	validateSteps(&v, r.Steps)
+	return v.err()
+}
+
+// validateSteps checks that steps are named uniquely, have a command and
+// only depend on other steps of the pipeline
+func validateSteps(v *validator, steps []PipelineStep) {
+	names := make(map[string]bool, len(steps))
+	for _, step := range steps {
+		names[step.Name] = true
+	}
+
+	seen := make(map[string]bool, len(steps))
+	for i, step := range steps {
+		field := fmt.Sprintf("Steps.%d.", i)
+		apiField := fmt.Sprintf("steps.%d.", i)
+
+		v.required(field+"Name", apiField+"name", step.Name)
+		if step.Name != "" && seen[step.Name] {
+			v.add(field+"Name", apiField+"name", "duplicate", fmt.Sprintf("duplicate step name %q", step.Name))
+		}
+		seen[step.Name] = true
+
+		v.required(field+"Command", apiField+"command", step.Command)
+		v.min(field+"Timeout", apiField+"timeout_seconds", step.Timeout, 0)
+		v.min(field+"Retries", apiField+"retries", step.Retries, 0)
+
+		for _, dep := range step.DependsOn {
+			if !names[dep] || dep == step.Name {
+				v.add(field+"DependsOn", apiField+"depends_on", "invalid", fmt.Sprintf("unknown step %q", dep))
+			}
+		}
+	}
+}
+
 // List retrieves all pipelines
 func (s *PipelinesService) List(ctx context.Context, opts *ListOptions) ([]*Pipeline, *Response, error) {
+	ctx, span := s.client.startSpan(ctx, "Pipelines.List")
+	defer span.End()
+
 	u := "pipelines"
 	req, err := s.client.NewRequestWithQuery(ctx, "GET", u, opts)
 	if err != nil {
@@ -49,8 +113,21 @@ func (s *PipelinesService) List(ctx context.Context, opts *ListOptions) ([]*Pipe
 	return pipelines, resp, nil
 }
 
+// ListIter returns an iterator over all pipelines across all pages
+func (s *PipelinesService) ListIter(ctx context.Context, opts *ListOptions) iter.Seq2[*Pipeline, error] {
+	return paginate(ctx, s.client, "Pipelines.ListIter", opts, s.List)
+}
+
+// ListAll returns all pipelines across all pages
+func (s *PipelinesService) ListAll(ctx context.Context, opts *ListOptions) ([]*Pipeline, error) {
+	return collect(paginate(ctx, s.client, "Pipelines.ListAll", opts, s.List))
+}
+
 // Get retrieves a single pipeline by ID
 func (s *PipelinesService) Get(ctx context.Context, id string) (*Pipeline, *Response, error) {
+	ctx, span := s.client.startSpan(ctx, "Pipelines.Get", Attribute{Key: attrPipelineID, Value: id})
+	defer span.End()
+
 	u := fmt.Sprintf("pipelines/%s", id)
 	req, err := s.client.NewRequest(ctx, "GET", u, nil)
 	if err != nil {
@@ -68,6 +145,9 @@ func (s *PipelinesService) Get(ctx context.Context, id string) (*Pipeline, *Resp
 
 // Create creates a new pipeline
 func (s *PipelinesService) Create(ctx context.Context, createReq *CreatePipelineRequest) (*Pipeline, *Response, error) {
+	ctx, span := s.client.startSpan(ctx, "Pipelines.Create")
+	defer span.End()
+
 	req, err := s.client.NewRequest(ctx, "POST", "pipelines", createReq)
 	if err != nil {
 		return nil, nil, err
@@ -84,6 +164,9 @@ func (s *PipelinesService) Create(ctx context.Context, createReq *CreatePipeline
 
 // Update updates an existing pipeline
 func (s *PipelinesService) Update(ctx context.Context, id string, updateReq *UpdatePipelineRequest) (*Pipeline, *Response, error) {
+	ctx, span := s.client.startSpan(ctx, "Pipelines.Update", Attribute{Key: attrPipelineID, Value: id})
+	defer span.End()
+
 	u := fmt.Sprintf("pipelines/%s", id)
 	req, err := s.client.NewRequest(ctx, "PUT", u, updateReq)
 	if err != nil {
@@ -99,8 +182,36 @@ func (s *PipelinesService) Update(ctx context.Context, id string, updateReq *Upd
 	return &pipeline, resp, nil
 }
 
+// Modify applies a read-modify-write change to a pipeline. It fetches the
+// pipeline, passes it to mutate to build the update, and sends the update
+// conditionally on the fetched version. When another change lands in
+// between, the pipeline is fetched again and mutate called with the new
+// version, up to 5 times. A nil update request from mutate leaves the
+// pipeline untouched.
+func (s *PipelinesService) Modify(ctx context.Context, id string, mutate func(pipeline *Pipeline) (*UpdatePipelineRequest, error)) (*Pipeline, *Response, error) {
+	ctx, span := s.client.startSpan(ctx, "Pipelines.Modify", Attribute{Key: attrPipelineID, Value: id})
+	defer span.End()
+
+	pipeline, resp, err := modify(ctx,
+		func(ctx context.Context) (*Pipeline, *Response, error) { return s.Get(ctx, id) },
+		mutate,
+		func(ctx context.Context, updateReq *UpdatePipelineRequest) (*Pipeline, *Response, error) {
+			return s.Update(ctx, id, updateReq)
+		},
+		func(pipeline *Pipeline) time.Time { return pipeline.UpdatedAt },
+	)
+	if err != nil {
+		span.RecordError(err)
+	}
+
+	return pipeline, resp, err
+}
+
 // Delete deletes a pipeline
 func (s *PipelinesService) Delete(ctx context.Context, id string) (*Response, error) {
+	ctx, span := s.client.startSpan(ctx, "Pipelines.Delete", Attribute{Key: attrPipelineID, Value: id})
+	defer span.End()
+
 	u := fmt.Sprintf("pipelines/%s", id)
 	req, err := s.client.NewRequest(ctx, "DELETE", u, nil)
 	if err != nil {
@@ -112,6 +223,9 @@ func (s *PipelinesService) Delete(ctx context.Context, id string) (*Response, er
 
 // Run triggers a pipeline run
 func (s *PipelinesService) Run(ctx context.Context, id string) (*PipelineRun, *Response, error) {
+	ctx, span := s.client.startSpan(ctx, "Pipelines.Run", Attribute{Key: attrPipelineID, Value: id})
+	defer span.End()
+
 	u := fmt.Sprintf("pipelines/%s/runs", id)
 	req, err := s.client.NewRequest(ctx, "POST", u, nil)
 	if err != nil {
@@ -129,6 +243,9 @@ func (s *PipelinesService) Run(ctx context.Context, id string) (*PipelineRun, *R
 
 // ListRuns retrieves all runs for a pipeline
 func (s *PipelinesService) ListRuns(ctx context.Context, pipelineID string, opts *ListOptions) ([]*PipelineRun, *Response, error) {
+	ctx, span := s.client.startSpan(ctx, "Pipelines.ListRuns", Attribute{Key: attrPipelineID, Value: pipelineID})
+	defer span.End()
+
 	u := fmt.Sprintf("pipelines/%s/runs", pipelineID)
 	req, err := s.client.NewRequestWithQuery(ctx, "GET", u, opts)
 	if err != nil {
@@ -144,8 +261,25 @@ func (s *PipelinesService) ListRuns(ctx context.Context, pipelineID string, opts
 	return runs, resp, nil
 }
 
+// ListRunsIter returns an iterator over all runs of a pipeline across all pages
+func (s *PipelinesService) ListRunsIter(ctx context.Context, pipelineID string, opts *ListOptions) iter.Seq2[*PipelineRun, error] {
+	return paginate(ctx, s.client, "Pipelines.ListRunsIter", opts, func(ctx context.Context, opts *ListOptions) ([]*PipelineRun, *Response, error) {
+		return s.ListRuns(ctx, pipelineID, opts)
+	}, Attribute{Key: attrPipelineID, Value: pipelineID})
+}
+
+// ListAllRuns returns all runs of a pipeline across all pages
+func (s *PipelinesService) ListAllRuns(ctx context.Context, pipelineID string, opts *ListOptions) ([]*PipelineRun, error) {
+	return collect(paginate(ctx, s.client, "Pipelines.ListAllRuns", opts, func(ctx context.Context, opts *ListOptions) ([]*PipelineRun, *Response, error) {
+		return s.ListRuns(ctx, pipelineID, opts)
+	}, Attribute{Key: attrPipelineID, Value: pipelineID}))
+}
+
 // GetRun retrieves a single pipeline run
 func (s *PipelinesService) GetRun(ctx context.Context, pipelineID string, runID string) (*PipelineRun, *Response, error) {
+	ctx, span := s.client.startSpan(ctx, "Pipelines.GetRun", Attribute{Key: attrPipelineID, Value: pipelineID}, Attribute{Key: attrPipelineRunID, Value: runID})
+	defer span.End()
+
 	u := fmt.Sprintf("pipelines/%s/runs/%s", pipelineID, runID)
 	req, err := s.client.NewRequest(ctx, "GET", u, nil)
 	if err != nil {
@@ -163,6 +297,9 @@ func (s *PipelinesService) GetRun(ctx context.Context, pipelineID string, runID
 
 // CancelRun cancels a pipeline run
 func (s *PipelinesService) CancelRun(ctx context.Context, pipelineID string, runID string) (*Response, error) {
+	ctx, span := s.client.startSpan(ctx, "Pipelines.CancelRun", Attribute{Key: attrPipelineID, Value: pipelineID}, Attribute{Key: attrPipelineRunID, Value: runID})
+	defer span.End()
+
 	u := fmt.Sprintf("pipelines/%s/runs/%s/cancel", pipelineID, runID)
 	req, err := s.client.NewRequest(ctx, "POST", u, nil)
 	if err != nil {
@@ -174,6 +311,9 @@ func (s *PipelinesService) CancelRun(ctx context.Context, pipelineID string, run
 
 // GetRunLogs retrieves logs for a pipeline run
 func (s *PipelinesService) GetRunLogs(ctx context.Context, pipelineID string, runID string) (string, *Response, error) {
+	ctx, span := s.client.startSpan(ctx, "Pipelines.GetRunLogs", Attribute{Key: attrPipelineID, Value: pipelineID}, Attribute{Key: attrPipelineRunID, Value: runID})
+	defer span.End()
+
 	u := fmt.Sprintf("pipelines/%s/runs/%s/logs", pipelineID, runID)
 	req, err := s.client.NewRequest(ctx, "GET", u, nil)
 	if err != nil {
@@ -193,6 +333,9 @@ func (s *PipelinesService) GetRunLogs(ctx context.Context, pipelineID string, ru
 
 // RetryRun retries a failed pipeline run
 func (s *PipelinesService) RetryRun(ctx context.Context, pipelineID string, runID string) (*PipelineRun, *Response, error) {
+	ctx, span := s.client.startSpan(ctx, "Pipelines.RetryRun", Attribute{Key: attrPipelineID, Value: pipelineID}, Attribute{Key: attrPipelineRunID, Value: runID})
+	defer span.End()
+
 	u := fmt.Sprintf("pipelines/%s/runs/%s/retry", pipelineID, runID)
 	req, err := s.client.NewRequest(ctx, "POST", u, nil)
 	if err != nil {
@@ -207,3 +350,44 @@ func (s *PipelinesService) RetryRun(ctx context.Context, pipelineID string, runI
 
 	return &run, resp, nil
 }
+
+// WaitForRun polls a pipeline run until it reaches a terminal status. Runs
+// that fail or are cancelled return a *StateError carrying the error message
+// of the first failed step.
+func (s *PipelinesService) WaitForRun(ctx context.Context, pipelineID, runID string, opts *WaitOptions[*PipelineRun]) (*PipelineRun, error) {
+	ctx, span := s.client.startSpan(ctx, "Pipelines.WaitForRun", Attribute{Key: attrPipelineID, Value: pipelineID}, Attribute{Key: attrPipelineRunID, Value: runID})
+	defer span.End()
+
+	w := &Waiter[*PipelineRun]{
+		Fetch: func(ctx context.Context) (*PipelineRun, error) {
+			run, _, err := s.GetRun(ctx, pipelineID, runID)
+			return run, err
+		},
+		State: func(run *PipelineRun) string {
+			return string(run.State)
+		},
+		Done: func(run *PipelineRun) (bool, error) {
+			if !run.State.IsTerminal() {
+				return false, nil
+			}
+			if run.State != StatusSuccess {
+				stateErr := &StateError{Resource: "pipeline run", ID: run.ID, State: string(run.State)}
+				for _, step := range run.Steps {
+					if step.ErrorMessage != "" {
+						stateErr.Message = step.Name + ": " + step.ErrorMessage
+						break
+					}
+				}
+				return true, stateErr
+			}
+			return true, nil
+		},
+	}
+
+	result, err := w.Wait(ctx, opts)
+	if err != nil {
+		span.RecordError(err)
+	}
+
+	return result, err
+}
@@ -0,0 +1,126 @@
+// Package promsevalla exports sevalla client metrics to Prometheus.
+//
+//	metrics := promsevalla.New(prometheus.DefaultRegisterer)
+//	client := sevalla.NewClient(
+//		sevalla.WithAPIKey(apiKey),
+//		sevalla.WithMetrics(metrics),
+//	)
+package promsevalla
+
+import (
+	"github.com/juststeveking/sevalla-go"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics implements sevalla.Metrics with Prometheus collectors
+type Metrics struct {
+	counters   map[string]*prometheus.CounterVec
+	histograms map[string]*prometheus.HistogramVec
+	gauges     map[string]*prometheus.GaugeVec
+}
+
+// Option is a function that configures Metrics
+type Option func(*config)
+
+type config struct {
+	namespace string
+	buckets   []float64
+}
+
+// WithNamespace prefixes every metric name with the given namespace
+func WithNamespace(namespace string) Option {
+	return func(c *config) {
+		c.namespace = namespace
+	}
+}
+
+// WithBuckets sets the latency histogram buckets in seconds,
+// prometheus.DefBuckets is used by default
+func WithBuckets(buckets []float64) Option {
+	return func(c *config) {
+		c.buckets = buckets
+	}
+}
+
+// New creates the sevalla collectors and registers them with reg
+func New(reg prometheus.Registerer, opts ...Option) *Metrics {
+	c := &config{buckets: prometheus.DefBuckets}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	operation := []string{sevalla.LabelService, sevalla.LabelMethod}
+	withStatus := []string{sevalla.LabelService, sevalla.LabelMethod, sevalla.LabelStatusClass}
+
+	m := &Metrics{
+		counters: map[string]*prometheus.CounterVec{
+			sevalla.MetricRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+				Namespace: c.namespace,
+				Name:      sevalla.MetricRequests,
+				Help:      "Number of requests sent to the Sevalla API.",
+			}, withStatus),
+			sevalla.MetricRequestErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+				Namespace: c.namespace,
+				Name:      sevalla.MetricRequestErrors,
+				Help:      "Number of failed requests to the Sevalla API.",
+			}, withStatus),
+			sevalla.MetricRetries: prometheus.NewCounterVec(prometheus.CounterOpts{
+				Namespace: c.namespace,
+				Name:      sevalla.MetricRetries,
+				Help:      "Number of retried requests to the Sevalla API.",
+			}, operation),
+		},
+		histograms: map[string]*prometheus.HistogramVec{
+			sevalla.MetricRequestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+				Namespace: c.namespace,
+				Name:      sevalla.MetricRequestDuration,
+				Help:      "Latency of requests to the Sevalla API in seconds.",
+				Buckets:   c.buckets,
+			}, operation),
+		},
+		gauges: map[string]*prometheus.GaugeVec{
+			sevalla.MetricRateLimitRemaining: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+				Namespace: c.namespace,
+				Name:      sevalla.MetricRateLimitRemaining,
+				Help:      "Remaining rate limit quota reported by the Sevalla API.",
+			}, nil),
+		},
+	}
+
+	for _, counter := range m.counters {
+		reg.MustRegister(counter)
+	}
+	for _, histogram := range m.histograms {
+		reg.MustRegister(histogram)
+	}
+	for _, gauge := range m.gauges {
+		reg.MustRegister(gauge)
+	}
+
+	return m
+}
+
+// AddCounter adds value to a counter. Unknown metrics are ignored.
+func (m *Metrics) AddCounter(name string, value float64, labels map[string]string) {
+	if counter, ok := m.counters[name]; ok {
+		counter.With(labels).Add(value)
+	}
+}
+
+// ObserveHistogram records a value in a histogram. Unknown metrics are
+// ignored.
+func (m *Metrics) ObserveHistogram(name string, value float64, labels map[string]string) {
+	if histogram, ok := m.histograms[name]; ok {
+		histogram.With(labels).Observe(value)
+	}
+}
+
+// SetGauge sets a gauge to value. Unknown metrics are ignored.
+func (m *Metrics) SetGauge(name string, value float64, labels map[string]string) {
+	if gauge, ok := m.gauges[name]; ok {
+		gauge.With(labels).Set(value)
+	}
+}
+
+// Compile-time assertion that Metrics implements sevalla.Metrics
+var _ sevalla.Metrics = (*Metrics)(nil)
@@ -0,0 +1,41 @@
+package promsevalla
+
+import (
+	"context"
+	"testing"
+
+	"github.com/juststeveking/sevalla-go"
+	"github.com/juststeveking/sevalla-go/sevallatest"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestMetrics(t *testing.T) {
+	srv := sevallatest.NewServer()
+	defer srv.Close()
+
+	reg := prometheus.NewRegistry()
+	metrics := New(reg)
+	client := srv.Client(sevalla.WithMetrics(metrics))
+
+	if _, _, err := client.Applications.List(context.Background(), nil); err != nil {
+		t.Fatalf("Applications.List returned error: %v", err)
+	}
+	if _, _, err := client.Applications.Get(context.Background(), "missing"); !sevalla.IsNotFound(err) {
+		t.Fatalf("Expected not found error, got %v", err)
+	}
+
+	requests := metrics.counters[sevalla.MetricRequests]
+	if got := testutil.ToFloat64(requests.WithLabelValues("Applications", "List", "2xx")); got != 1 {
+		t.Errorf("Expected 1 List request, got %v", got)
+	}
+
+	errs := metrics.counters[sevalla.MetricRequestErrors]
+	if got := testutil.ToFloat64(errs.WithLabelValues("Applications", "Get", "4xx")); got != 1 {
+		t.Errorf("Expected 1 Get error, got %v", got)
+	}
+
+	if got := testutil.CollectAndCount(metrics.histograms[sevalla.MetricRequestDuration]); got != 2 {
+		t.Errorf("Expected 2 latency series, got %d", got)
+	}
+}
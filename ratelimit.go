@@ -0,0 +1,197 @@
+package sevalla
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// RateLimiter is a client-side token bucket that paces requests made by a
+// Client. Besides its own fixed rate, it adjusts itself from the Rate values
+// reported by the API so that the remaining quota is spread evenly until the
+// reset time, and it holds every request back while the quota is exhausted.
+//
+// A RateLimiter is safe for concurrent use and may be shared between clients
+// that use the same API key.
+type RateLimiter struct {
+	mu sync.Mutex
+
+	// Configured limit in requests per second, 0 means no fixed limit
+	limit float64
+	burst float64
+
+	tokens float64
+	last   time.Time
+
+	// Limit derived from the server's remaining quota, valid until reset
+	serverLimit float64
+	reset       time.Time
+
+	// No request may be sent before this time
+	blockedUntil time.Time
+}
+
+// NewRateLimiter returns a RateLimiter allowing limit requests per second
+// with bursts of up to burst requests. A limit of 0 or less disables the
+// fixed rate so that only the quota reported by the API is enforced.
+func NewRateLimiter(limit float64, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &RateLimiter{
+		limit:  math.Max(limit, 0),
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// WithRateLimiter paces every request sent by the client through the given
+// rate limiter
+func WithRateLimiter(limiter *RateLimiter) ClientOption {
+	return func(c *Client) {
+		c.rateLimiter = limiter
+	}
+}
+
+// Wait blocks until a request may be sent or the context is done. It fails
+// immediately when the context deadline would expire before that.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		delay := l.reserve(time.Now())
+		if delay == 0 {
+			return nil
+		}
+
+		if !sleepContext(ctx, delay) {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			return context.DeadlineExceeded
+		}
+	}
+}
+
+// WaitTime returns how long a request would currently have to wait
+func (l *RateLimiter) WaitTime() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.delay(time.Now())
+}
+
+// Update adjusts the limiter from the rate limit state reported by the API.
+// Retry-After is always honoured. Remaining and Reset are applied only when
+// HasQuota is set, as it is on the Rate of a response carrying both headers,
+// spreading the Remaining requests until Reset. Limit is not used. Set
+// HasQuota when building a Rate by hand.
+func (l *RateLimiter) Update(rate Rate) {
+	now := time.Now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.advance(now)
+
+	if rate.RetryAfter > 0 {
+		if until := now.Add(rate.RetryAfter); until.After(l.blockedUntil) {
+			l.blockedUntil = until
+		}
+	}
+
+	if !rate.HasQuota || !rate.Reset.After(now) {
+		return
+	}
+
+	l.reset = rate.Reset
+	if rate.Remaining <= 0 {
+		l.serverLimit = 0
+		l.tokens = 0
+		if rate.Reset.After(l.blockedUntil) {
+			l.blockedUntil = rate.Reset
+		}
+		return
+	}
+
+	l.serverLimit = float64(rate.Remaining) / rate.Reset.Sub(now).Seconds()
+	l.tokens = math.Min(l.tokens, float64(rate.Remaining))
+}
+
+// reserve takes a token if one is available, otherwise it returns how long
+// to wait before trying again
+func (l *RateLimiter) reserve(now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delay := l.delay(now)
+	if delay == 0 {
+		l.tokens--
+	}
+
+	return delay
+}
+
+// delay refills the bucket and returns the time until a token is available
+func (l *RateLimiter) delay(now time.Time) time.Duration {
+	l.advance(now)
+
+	if now.Before(l.blockedUntil) {
+		return l.blockedUntil.Sub(now)
+	}
+
+	if l.tokens >= 1 {
+		return 0
+	}
+
+	limit := l.effectiveLimit(now)
+	if math.IsInf(limit, 1) {
+		l.tokens = l.burst
+		return 0
+	}
+
+	return time.Duration((1 - l.tokens) / limit * float64(time.Second))
+}
+
+// advance adds the tokens accumulated since the last call
+func (l *RateLimiter) advance(now time.Time) {
+	if !l.reset.IsZero() && !now.Before(l.reset) {
+		// The server window has passed, fall back to the configured rate
+		l.reset = time.Time{}
+		l.serverLimit = 0
+		l.tokens = math.Max(l.tokens, 1)
+	}
+
+	elapsed := now.Sub(l.last).Seconds()
+	l.last = now
+	if elapsed <= 0 {
+		return
+	}
+
+	limit := l.effectiveLimit(now)
+	if math.IsInf(limit, 1) {
+		l.tokens = l.burst
+		return
+	}
+
+	l.tokens = math.Min(l.burst, l.tokens+elapsed*limit)
+}
+
+// effectiveLimit returns the stricter of the configured and server rates
+func (l *RateLimiter) effectiveLimit(now time.Time) float64 {
+	limit := math.Inf(1)
+	if l.limit > 0 {
+		limit = l.limit
+	}
+
+	if !l.reset.IsZero() && now.Before(l.reset) && l.serverLimit > 0 {
+		limit = math.Min(limit, l.serverLimit)
+	}
+
+	return limit
+}
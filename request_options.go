@@ -0,0 +1,103 @@
+package sevalla
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// RequestOption configures a single API call. Request options travel in the
+// context passed to a service method, see WithRequestOptions.
+type RequestOption func(*requestOptions)
+
+// requestOptions holds the per-call settings carried by a context
+type requestOptions struct {
+	header         http.Header
+	timeout        time.Duration
+	retryPolicy    *RetryPolicy
+	hasRetryPolicy bool
+	idempotencyKey string
+}
+
+// requestOptionsKey is the context key holding the request options
+type requestOptionsKey struct{}
+
+// WithRequestOptions returns a context that applies the given options to
+// every request sent with it. Options are added to those already carried by
+// ctx, later options taking precedence.
+//
+//	ctx := sevalla.WithRequestOptions(ctx,
+//		sevalla.RequestHeader("X-Correlation-Id", id),
+//		sevalla.RequestTimeout(10*time.Second),
+//	)
+//	app, _, err := client.Applications.Get(ctx, appID)
+func WithRequestOptions(ctx context.Context, opts ...RequestOption) context.Context {
+	o := &requestOptions{header: http.Header{}}
+	if current := requestOptionsFromContext(ctx); current != nil {
+		*o = *current
+		o.header = current.header.Clone()
+	}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return context.WithValue(ctx, requestOptionsKey{}, o)
+}
+
+// requestOptionsFromContext returns the request options carried by ctx, or
+// nil when there are none
+func requestOptionsFromContext(ctx context.Context) *requestOptions {
+	o, _ := ctx.Value(requestOptionsKey{}).(*requestOptions)
+	return o
+}
+
+// RequestHeader sets a header on the request, replacing any value set by
+// the client
+func RequestHeader(key, value string) RequestOption {
+	return func(o *requestOptions) {
+		o.header.Set(key, value)
+	}
+}
+
+// RequestTimeout bounds the time a call may take, including retries
+func RequestTimeout(d time.Duration) RequestOption {
+	return func(o *requestOptions) {
+		o.timeout = d
+	}
+}
+
+// RequestRetryPolicy overrides the client's retry policy. Passing nil
+// disables retries.
+func RequestRetryPolicy(policy *RetryPolicy) RequestOption {
+	return func(o *requestOptions) {
+		o.retryPolicy = policy
+		o.hasRetryPolicy = true
+	}
+}
+
+// RequestIdempotencyKey sends the given Idempotency-Key with every POST
+// request made with the context instead of a generated one
+func RequestIdempotencyKey(key string) RequestOption {
+	return func(o *requestOptions) {
+		o.idempotencyKey = key
+	}
+}
+
+// applyRequestHeaders sets the headers carried by the request options
+func applyRequestHeaders(ctx context.Context, req *http.Request) {
+	if o := requestOptionsFromContext(ctx); o != nil {
+		for key, values := range o.header {
+			req.Header[key] = append([]string(nil), values...)
+		}
+	}
+}
+
+// requestRetryPolicy returns the retry policy for a request, falling back
+// to the given default
+func requestRetryPolicy(ctx context.Context, policy *RetryPolicy) *RetryPolicy {
+	if o := requestOptionsFromContext(ctx); o != nil && o.hasRetryPolicy {
+		return o.retryPolicy
+	}
+	return policy
+}
@@ -0,0 +1,168 @@
+package sevalla
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"time"
+)
+
+const (
+	// DefaultMaxAttempts is the default number of attempts made for a request
+	DefaultMaxAttempts = 3
+
+	// DefaultRetryBaseDelay is the default delay before the first retry
+	DefaultRetryBaseDelay = 500 * time.Millisecond
+
+	// DefaultRetryMaxDelay is the default upper bound for a single retry delay
+	DefaultRetryMaxDelay = 30 * time.Second
+
+	// DefaultRetryJitter is the default fraction of each delay that is randomized
+	DefaultRetryJitter = 0.2
+)
+
+// RetryPolicy configures how Client.Do retries failed requests.
+//
+// A request is retried when the transport returns an error (other than the
+// request context being cancelled or expiring) or when the API responds with
+// one of RetryableStatusCodes. Delays grow exponentially from BaseDelay and
+// are capped at MaxDelay.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one.
+	// Values below 1 are treated as 1.
+	MaxAttempts int
+
+	// BaseDelay is the delay before the first retry. Each following retry
+	// doubles the previous delay.
+	BaseDelay time.Duration
+
+	// MaxDelay caps the delay between two attempts.
+	MaxDelay time.Duration
+
+	// Jitter is the fraction (0 to 1) of each delay that is randomized to
+	// avoid many clients retrying in lockstep.
+	Jitter float64
+
+	// RetryableStatusCodes lists the HTTP status codes that trigger a retry.
+	// When nil, API errors are retried when IsTemporary reports them as
+	// temporary.
+	RetryableStatusCodes []int
+}
+
+// DefaultRetryPolicy returns a RetryPolicy with sensible defaults
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:          DefaultMaxAttempts,
+		BaseDelay:            DefaultRetryBaseDelay,
+		MaxDelay:             DefaultRetryMaxDelay,
+		Jitter:               DefaultRetryJitter,
+		RetryableStatusCodes: slices.Clone(temporaryStatusCodes),
+	}
+}
+
+// WithRetryPolicy enables automatic retries using the given policy.
+// Passing nil disables retries.
+func WithRetryPolicy(policy *RetryPolicy) ClientOption {
+	return func(c *Client) {
+		c.retryPolicy = policy
+	}
+}
+
+// maxAttempts returns the number of attempts allowed by the policy
+func (p *RetryPolicy) maxAttempts() int {
+	if p == nil || p.MaxAttempts < 1 {
+		return 1
+	}
+	return p.MaxAttempts
+}
+
+// shouldRetry reports whether a failed attempt is worth retrying
+func (p *RetryPolicy) shouldRetry(req *http.Request, resp *Response, err error) bool {
+	if err == nil {
+		return false
+	}
+
+	// Never retry once the caller has given up on the request
+	if req.Context().Err() != nil {
+		return false
+	}
+
+	// Transport level failures have no response
+	if resp == nil {
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+
+	if p.RetryableStatusCodes == nil {
+		return IsTemporary(err)
+	}
+
+	return slices.Contains(p.RetryableStatusCodes, resp.StatusCode)
+}
+
+// backoff returns the delay to wait before the given retry (1-based)
+func (p *RetryPolicy) backoff(retry int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < retry; i++ {
+		delay *= 2
+		if p.MaxDelay > 0 && delay >= p.MaxDelay {
+			break
+		}
+	}
+
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+
+	if p.Jitter > 0 && delay > 0 {
+		jitter := p.Jitter
+		if jitter > 1 {
+			jitter = 1
+		}
+		spread := time.Duration(float64(delay) * jitter)
+		if spread > 0 {
+			delay = delay - spread + rand.N(spread+1)
+		}
+	}
+
+	return delay
+}
+
+// rewindBody resets the request body so it can be sent again
+func rewindBody(req *http.Request) error {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil
+	}
+
+	if req.GetBody == nil {
+		return errors.New("sevalla: request body cannot be rewound for retry")
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return err
+	}
+	req.Body = body
+
+	return nil
+}
+
+// sleepContext waits for the given delay or until the context is done.
+// It returns false without waiting when the context deadline would expire
+// before the delay elapses.
+func sleepContext(ctx context.Context, delay time.Duration) bool {
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+		return false
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,101 @@
+package sevalla
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+)
+
+// Secret is a sensitive string, such as a database password or an
+// environment variable value. It is redacted when formatted with fmt, logged
+// with slog or encoded as JSON. Use Reveal to read the value.
+//
+// Secrets decode from plain JSON strings, so API responses fill them as
+// usual.
+type Secret string
+
+// Reveal returns the secret value
+func (s Secret) Reveal() string {
+	return string(s)
+}
+
+// String returns a placeholder for a non-empty secret
+func (s Secret) String() string {
+	if s == "" {
+		return ""
+	}
+	return redacted
+}
+
+// GoString returns a placeholder for a non-empty secret, for the %#v verb
+func (s Secret) GoString() string {
+	return fmt.Sprintf("sevalla.Secret(%q)", s.String())
+}
+
+// LogValue returns a placeholder for a non-empty secret
+func (s Secret) LogValue() slog.Value {
+	return slog.StringValue(s.String())
+}
+
+// MarshalJSON encodes a placeholder for a non-empty secret
+func (s Secret) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
+
+// RevealSecrets returns the values of a map of secrets, e.g. to copy
+// Application.EnvironmentVars into an update request
+func RevealSecrets(secrets map[string]Secret) map[string]string {
+	if secrets == nil {
+		return nil
+	}
+
+	values := make(map[string]string, len(secrets))
+	for key, secret := range secrets {
+		values[key] = secret.Reveal()
+	}
+	return values
+}
+
+// Redacted wraps a value of the model graph, such as a request, a resource
+// or a slice of them, so that it is formatted and logged with passwords and
+// environment variable values redacted. Unlike Secret, it also covers
+// plain string fields, such as the EnvironmentVars of request types.
+//
+//	logger.Info("creating application", "request", sevalla.Redact(createReq))
+type Redacted struct {
+	v interface{}
+}
+
+// Redact wraps v for redacted formatting and logging
+func Redact(v interface{}) Redacted {
+	return Redacted{v: v}
+}
+
+// String returns the value as redacted JSON
+func (r Redacted) String() string {
+	data, err := json.Marshal(r.v)
+	if err != nil {
+		return redacted
+	}
+	return redactBody("", data)
+}
+
+// Format writes the value as redacted JSON for every verb
+func (r Redacted) Format(f fmt.State, _ rune) {
+	_, _ = fmt.Fprint(f, r.String())
+}
+
+// LogValue returns the value as redacted, decoded JSON, which slog handlers
+// render as nested groups or objects
+func (r Redacted) LogValue() slog.Value {
+	var data interface{}
+	if err := json.Unmarshal([]byte(r.String()), &data); err != nil {
+		return slog.StringValue(redacted)
+	}
+	return slog.AnyValue(data)
+}
+
+// MarshalJSON encodes the value as redacted JSON
+func (r Redacted) MarshalJSON() ([]byte, error) {
+	return []byte(r.String()), nil
+}
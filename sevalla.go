@@ -6,6 +6,8 @@ import (
 	"context"
 	"encoding/json"
 	"io"
+	"log/slog"
+	"math"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -37,12 +39,48 @@ type Client struct {
 	// Base URL for API requests
 	baseURL *url.URL
 
-	// API key for authentication
-	apiKey string
+	// Credentials provider for authentication
+	credentials CredentialsProvider
 
 	// User agent for requests
 	userAgent string
 
+	// HTTP client timeout set by WithTimeout
+	timeout time.Duration
+
+	// Company ID added to list requests that do not set one
+	companyID string
+
+	// Retry policy applied by Do, nil disables retries
+	retryPolicy *RetryPolicy
+
+	// Client-side rate limiter, nil disables pacing
+	rateLimiter *RateLimiter
+
+	// Logger for API calls, nil disables logging
+	logger *slog.Logger
+
+	// Tracer for service operations, nil disables tracing
+	tracer Tracer
+
+	// Whether request bodies are validated before sending
+	validate bool
+
+	// Cache for conditional GET requests, nil disables caching
+	cache Cache
+
+	// Metrics recorder, nil disables metrics
+	metrics Metrics
+
+	// Plan recording mutating requests, nil disables dry-run mode
+	dryRun *DryRunPlan
+
+	// Middlewares wrapping every request sent by Do
+	middlewares []Middleware
+
+	// Middleware chain built from the options
+	doer Doer
+
 	// Services
 	Applications *ApplicationsService
 	Databases    *DatabasesService
@@ -54,11 +92,10 @@ type Client struct {
 // ClientOption is a function that configures a Client
 type ClientOption func(*Client)
 
-// WithAPIKey sets the API key for authentication
+// WithAPIKey sets a static API key for authentication. Use WithCredentials
+// for keys that are rotated.
 func WithAPIKey(key string) ClientOption {
-	return func(c *Client) {
-		c.apiKey = key
-	}
+	return WithCredentials(StaticCredentials(key))
 }
 
 // WithHTTPClient sets a custom HTTP client
@@ -77,6 +114,24 @@ func WithBaseURL(baseURL string) ClientOption {
 	}
 }
 
+// WithTimeout sets the timeout of the HTTP client, DefaultTimeout by
+// default. It applies to a copy of any client set by WithHTTPClient.
+func WithTimeout(d time.Duration) ClientOption {
+	return func(c *Client) {
+		c.timeout = d
+	}
+}
+
+// WithCompanyID sets the company ID used by the top-level List methods of
+// the applications, databases, deployments, pipelines and static sites
+// services when ListOptions.CompanyID is empty. Nested lists, such as the
+// runs of a pipeline, and log streams are not filtered by company.
+func WithCompanyID(id string) ClientOption {
+	return func(c *Client) {
+		c.companyID = id
+	}
+}
+
 // WithUserAgent sets a custom user agent
 func WithUserAgent(ua string) ClientOption {
 	return func(c *Client) {
@@ -99,6 +154,14 @@ func NewClient(opts ...ClientOption) *Client {
 		opt(c)
 	}
 
+	if c.timeout > 0 {
+		client := *c.client
+		client.Timeout = c.timeout
+		c.client = &client
+	}
+
+	c.doer = c.buildChain()
+
 	// Initialize services
 	c.Applications = &ApplicationsService{client: c}
 	c.Databases = &DatabasesService{client: c}
@@ -116,6 +179,14 @@ func (c *Client) NewRequest(ctx context.Context, method, urlStr string, body int
 		return nil, err
 	}
 
+	if c.validate {
+		if v, ok := body.(Validator); ok {
+			if err := v.Validate(); err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	var buf io.ReadWriter
 	if body != nil {
 		buf = &bytes.Buffer{}
@@ -136,14 +207,29 @@ func (c *Client) NewRequest(ctx context.Context, method, urlStr string, body int
 	}
 
 	// Set authentication
-	if c.apiKey != "" {
-		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	if err := c.authenticate(ctx, req); err != nil {
+		return nil, err
 	}
 
 	// Set user agent
 	req.Header.Set("User-Agent", c.userAgent)
 
-	return req, nil
+	// Make mutating requests safe to retry
+	setIdempotencyKey(ctx, req)
+
+	applyRequestHeaders(ctx, req)
+
+	return withBodyType(req, body), nil
+}
+
+// companyListPaths are the list endpoints filtered by the company_id query
+// parameter, which receive the client's company ID
+var companyListPaths = map[string]bool{
+	"applications": true,
+	"databases":    true,
+	"deployments":  true,
+	"pipelines":    true,
+	"static-sites": true,
 }
 
 // NewRequestWithQuery creates an API request with query parameters
@@ -161,24 +247,59 @@ func (c *Client) NewRequestWithQuery(ctx context.Context, method, urlStr string,
 		u.RawQuery = v.Encode()
 	}
 
+	if c.companyID != "" && companyListPaths[urlStr] {
+		q := u.Query()
+		if q.Get("company_id") == "" {
+			q.Set("company_id", c.companyID)
+			u.RawQuery = q.Encode()
+		}
+	}
+
 	req, err := http.NewRequestWithContext(ctx, method, u.String(), nil)
 	if err != nil {
 		return nil, err
 	}
 
 	// Set authentication
-	if c.apiKey != "" {
-		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	if err := c.authenticate(ctx, req); err != nil {
+		return nil, err
 	}
 
 	// Set user agent
 	req.Header.Set("User-Agent", c.userAgent)
 
+	applyRequestHeaders(ctx, req)
+
 	return req, nil
 }
 
-// Do executes an API request and returns the response
+// Do executes an API request through the client's middleware chain and
+// returns the response. When a retry policy is configured, failed attempts
+// are retried with the request body rewound. The timeout and retry policy
+// of the request options in the request context are honoured.
 func (c *Client) Do(req *http.Request, v interface{}) (*Response, error) {
+	if o := requestOptionsFromContext(req.Context()); o != nil && o.timeout > 0 {
+		ctx, cancel := context.WithTimeout(req.Context(), o.timeout)
+		defer cancel()
+		req = req.WithContext(ctx)
+	}
+
+	return c.doer.Do(req, v)
+}
+
+// send performs a single attempt of an API request
+func (c *Client) send(req *http.Request, v interface{}) (*Response, error) {
+	if stream, ok := v.(*streamBody); ok {
+		return c.sendStream(req, stream)
+	}
+
+	cacheable := c.cacheable(req, v)
+
+	var cached *CacheEntry
+	if cacheable {
+		req, cached = c.revalidate(req)
+	}
+
 	resp, err := c.client.Do(req)
 	if err != nil {
 		return nil, err
@@ -188,12 +309,23 @@ func (c *Client) Do(req *http.Request, v interface{}) (*Response, error) {
 		_ = resp.Body.Close()
 	}()
 
-	response := &Response{Response: resp}
-	response.populatePageValues()
+	var data []byte
+	cacheHit := cached != nil && resp.StatusCode == http.StatusNotModified
+	if cacheHit {
+		data = notModified(resp, cached)
+	}
+
+	response := newResponse(resp)
+	response.CacheHit = cacheHit
 
 	// Check for errors
-	if err := CheckResponse(resp); err != nil {
-		return response, err
+	if !cacheHit {
+		if err := CheckResponse(resp); err != nil {
+			if cacheable {
+				c.storeResponse(req, resp, nil)
+			}
+			return response, err
+		}
 	}
 
 	// Decode response body if v is provided
@@ -201,12 +333,22 @@ func (c *Client) Do(req *http.Request, v interface{}) (*Response, error) {
 		if w, ok := v.(io.Writer); ok {
 			_, err = io.Copy(w, resp.Body)
 		} else {
-			decErr := json.NewDecoder(resp.Body).Decode(v)
+			if !cacheHit {
+				var readErr error
+				data, readErr = io.ReadAll(resp.Body)
+				if readErr != nil {
+					return response, readErr
+				}
+			}
+
+			decErr := json.NewDecoder(bytes.NewReader(data)).Decode(v)
 			if decErr == io.EOF {
 				decErr = nil // Ignore EOF errors from empty response
 			}
 			if decErr != nil {
-				err = decErr
+				err = newDecodeError(resp, data, decErr)
+			} else if cacheable && !cacheHit {
+				c.storeResponse(req, resp, data)
 			}
 		}
 	}
@@ -226,6 +368,34 @@ type Response struct {
 
 	// Rate limiting
 	Rate Rate
+
+	// IdempotentReplayed reports whether the API returned the stored result
+	// of an earlier request with the same idempotency key
+	IdempotentReplayed bool
+
+	// ETag identifies the version of the returned resource. Pass it to
+	// RequestIfMatch to make an update conditional on that version.
+	ETag string
+
+	// CacheHit reports whether the API answered 304 Not Modified and the
+	// body was decoded from the cache set by WithCache. StatusCode remains
+	// 304.
+	CacheHit bool
+
+	// DryRun reports whether the request was recorded by a client in
+	// dry-run mode instead of being sent. See WithDryRun.
+	DryRun bool
+}
+
+// newResponse wraps an HTTP response, populating the values read from its
+// headers
+func newResponse(resp *http.Response) *Response {
+	response := &Response{Response: resp}
+	response.populatePageValues()
+	response.populateRateValues()
+	response.IdempotentReplayed = resp.Header.Get(headerIdempotentReplayed) == "true"
+	response.ETag = resp.Header.Get("ETag")
+	return response
 }
 
 // populatePageValues populates the pagination values from Link header
@@ -272,14 +442,107 @@ func (r *Response) populatePageValues() {
 	}
 }
 
+// populateRateValues populates the rate limit values from response headers
+func (r *Response) populateRateValues() {
+	r.Rate = parseRate(r.Response)
+}
+
 // Rate represents the rate limit information
 type Rate struct {
 	Limit     int
 	Remaining int
 	Reset     time.Time
+
+	// HasQuota reports whether the response carried both the remaining
+	// requests and the reset time, so that Remaining and Reset describe the
+	// current quota rather than missing headers
+	HasQuota bool
+
+	// RetryAfter is how long the server asked clients to wait before
+	// sending another request, taken from the Retry-After header
+	RetryAfter time.Duration
+}
+
+// Rate limit headers returned by the API
+const (
+	headerRateLimit     = "X-RateLimit-Limit"
+	headerRateRemaining = "X-RateLimit-Remaining"
+	headerRateReset     = "X-RateLimit-Reset"
+	headerRetryAfter    = "Retry-After"
+)
+
+// parseRate parses the rate limit headers of an HTTP response. The reset
+// header is expected to hold a Unix timestamp in seconds, and Retry-After
+// may be either a number of seconds or an HTTP date.
+func parseRate(r *http.Response) Rate {
+	var rate Rate
+	if r == nil {
+		return rate
+	}
+
+	if limit := r.Header.Get(headerRateLimit); limit != "" {
+		rate.Limit, _ = strconv.Atoi(limit)
+	}
+
+	if remaining := r.Header.Get(headerRateRemaining); remaining != "" {
+		rate.Remaining, _ = strconv.Atoi(remaining)
+	}
+
+	if reset := r.Header.Get(headerRateReset); reset != "" {
+		if v, err := strconv.ParseInt(reset, 10, 64); err == nil && v > 0 {
+			rate.Reset = time.Unix(v, 0)
+		}
+	}
+
+	rate.HasQuota = hasRateQuota(r)
+
+	if retryAfter := r.Header.Get(headerRetryAfter); retryAfter != "" {
+		rate.RetryAfter = parseRetryAfter(retryAfter, time.Now())
+		if rate.Reset.IsZero() && rate.RetryAfter > 0 {
+			rate.Reset = time.Now().Add(rate.RetryAfter)
+		}
+	}
+
+	return rate
+}
+
+// hasRateQuota reports whether a response carries both the remaining
+// requests and the reset time of the rate limit, which is all the limiter
+// needs to pace requests
+func hasRateQuota(resp *http.Response) bool {
+	return hasRateRemaining(resp) && resp.Header.Get(headerRateReset) != ""
 }
 
-// CheckResponse checks the API response for errors
+// hasRateRemaining reports whether a response carries the number of
+// remaining requests, with or without the limit it counts down from
+func hasRateRemaining(resp *http.Response) bool {
+	return resp != nil && resp.Header.Get(headerRateRemaining) != ""
+}
+
+// parseRetryAfter parses a Retry-After header value relative to now
+func parseRetryAfter(v string, now time.Time) time.Duration {
+	v = strings.TrimSpace(v)
+
+	if seconds, err := strconv.Atoi(v); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+
+	if t, err := http.ParseTime(v); err == nil {
+		if d := t.Sub(now); d > 0 {
+			return d
+		}
+	}
+
+	return 0
+}
+
+// CheckResponse checks the API response for errors. A 429 response is
+// returned as a *RateLimitError, a rejected idempotency key as an
+// *IdempotencyError, any other 422 response as *ValidationErrors, and every
+// other failure as an *ErrorResponse.
 func CheckResponse(r *http.Response) error {
 	if c := r.StatusCode; c >= 200 && c <= 299 {
 		return nil
@@ -293,6 +556,27 @@ func CheckResponse(r *http.Response) error {
 		}
 	}
 
+	if r.StatusCode == http.StatusTooManyRequests {
+		rate := parseRate(r)
+		return &RateLimitError{
+			ErrorResponse: errorResponse,
+			Rate:          rate,
+			RetryAfter:    int(math.Ceil(rate.RetryAfter.Seconds())),
+		}
+	}
+
+	if idempotencyErr, ok := asIdempotencyError(errorResponse); ok {
+		return idempotencyErr
+	}
+
+	if r.StatusCode == http.StatusUnprocessableEntity {
+		return newValidationErrors(errorResponse)
+	}
+
+	if r.StatusCode == http.StatusPreconditionFailed {
+		return newPreconditionFailedError(errorResponse)
+	}
+
 	return errorResponse
 }
 
@@ -4,10 +4,21 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
 	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -747,8 +758,8 @@ func TestApplicationsService_GetEnvironmentVariables(t *testing.T) {
 		t.Fatalf("Applications.GetEnvironmentVariables returned error: %v", err)
 	}
 
-	if vars["DATABASE_URL"] != want["DATABASE_URL"] {
-		t.Errorf("Expected DATABASE_URL %s, got %s", want["DATABASE_URL"], vars["DATABASE_URL"])
+	if vars["DATABASE_URL"].Reveal() != want["DATABASE_URL"] {
+		t.Errorf("Expected DATABASE_URL %s, got %s", want["DATABASE_URL"], vars["DATABASE_URL"].Reveal())
 	}
 }
 
@@ -911,8 +922,8 @@ func TestClientOptions(t *testing.T) {
 	)
 
 	// Check API key
-	if client.apiKey != "custom-key" {
-		t.Errorf("Expected API key 'custom-key', got %s", client.apiKey)
+	if client.credentials != StaticCredentials("custom-key") {
+		t.Errorf("Expected API key 'custom-key', got %v", client.credentials)
 	}
 
 	// Check HTTP client
@@ -1174,9 +1185,7 @@ func TestDatabasesService_GetCredentials(t *testing.T) {
 			t.Errorf("Expected GET method, got %s", r.Method)
 		}
 		w.Header().Set("Content-Type", "application/json")
-		if err := json.NewEncoder(w).Encode(want); err != nil {
-			t.Fatalf("Failed to encode response: %v", err)
-		}
+		_, _ = w.Write([]byte(`{"id":"db-1","internal_url":"postgresql://db.example.com:5432","username":"dbuser","password":"secret"}`))
 	})
 
 	ctx := context.Background()
@@ -1186,8 +1195,8 @@ func TestDatabasesService_GetCredentials(t *testing.T) {
 		t.Fatalf("Databases.GetCredentials returned error: %v", err)
 	}
 
-	if db.Username != want.Username {
-		t.Errorf("Expected username %s, got %s", want.Username, db.Username)
+	if db.Username.Reveal() != want.Username.Reveal() {
+		t.Errorf("Expected username %s, got %s", want.Username.Reveal(), db.Username.Reveal())
 	}
 }
 
@@ -1211,9 +1220,7 @@ func TestDatabasesService_ResetPassword(t *testing.T) {
 			t.Errorf("Expected POST method, got %s", r.Method)
 		}
 		w.Header().Set("Content-Type", "application/json")
-		if err := json.NewEncoder(w).Encode(want); err != nil {
-			t.Fatalf("Failed to encode response: %v", err)
-		}
+		_, _ = w.Write([]byte(`{"id":"db-1","password":"new-secret"}`))
 	})
 
 	ctx := context.Background()
@@ -1223,8 +1230,8 @@ func TestDatabasesService_ResetPassword(t *testing.T) {
 		t.Fatalf("Databases.ResetPassword returned error: %v", err)
 	}
 
-	if db.Password != want.Password {
-		t.Errorf("Expected password %s, got %s", want.Password, db.Password)
+	if db.Password.Reveal() != want.Password.Reveal() {
+		t.Errorf("Expected password %s, got %s", want.Password.Reveal(), db.Password.Reveal())
 	}
 }
 
@@ -2703,16 +2710,3144 @@ func TestRateLimitError(t *testing.T) {
 	}
 }
 
-// Helper function
-func contains(s, substr string) bool {
-	return len(s) >= len(substr) && (s == substr || len(s) > len(substr) && (s[:len(substr)] == substr || s[len(s)-len(substr):] == substr || containsMiddle(s, substr)))
+// Retry Tests
+
+func TestClient_DoRetriesRetryableStatus(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := NewClient(
+		WithAPIKey("test-key"),
+		WithBaseURL(server.URL),
+		WithRetryPolicy(&RetryPolicy{
+			MaxAttempts:          3,
+			BaseDelay:            time.Millisecond,
+			MaxDelay:             5 * time.Millisecond,
+			RetryableStatusCodes: []int{http.StatusServiceUnavailable},
+		}),
+	)
+
+	var attempts int
+	mux.HandleFunc("/applications", func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+
+		var got CreateApplicationRequest
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("Attempt %d: failed to decode request body: %v", attempts, err)
+		}
+		if got.Name != "retry-app" {
+			t.Errorf("Attempt %d: expected name 'retry-app', got %q", attempts, got.Name)
+		}
+
+		if attempts < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(&Application{ID: "app-1", Name: got.Name})
+	})
+
+	app, _, err := client.Applications.Create(context.Background(), &CreateApplicationRequest{Name: "retry-app"})
+	if err != nil {
+		t.Fatalf("Applications.Create returned error: %v", err)
+	}
+
+	if attempts != 3 {
+		t.Errorf("Expected 3 attempts, got %d", attempts)
+	}
+
+	if app.ID != "app-1" {
+		t.Errorf("Expected app ID 'app-1', got %s", app.ID)
+	}
 }
 
-func containsMiddle(s, substr string) bool {
-	for i := 0; i <= len(s)-len(substr); i++ {
-		if s[i:i+len(substr)] == substr {
-			return true
+func TestClient_DoDoesNotRetryClientErrors(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	policy := DefaultRetryPolicy()
+	policy.BaseDelay = time.Millisecond
+
+	client := NewClient(
+		WithAPIKey("test-key"),
+		WithBaseURL(server.URL),
+		WithRetryPolicy(policy),
+	)
+
+	var attempts int
+	mux.HandleFunc("/applications/app-1", func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	_, _, err := client.Applications.Get(context.Background(), "app-1")
+	if !IsNotFound(err) {
+		t.Fatalf("Expected not found error, got %v", err)
+	}
+
+	if attempts != 1 {
+		t.Errorf("Expected 1 attempt, got %d", attempts)
+	}
+}
+
+func TestClient_DoRetriesGiveUpAfterMaxAttempts(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	policy := DefaultRetryPolicy()
+	policy.MaxAttempts = 2
+	policy.BaseDelay = time.Millisecond
+
+	client := NewClient(
+		WithAPIKey("test-key"),
+		WithBaseURL(server.URL),
+		WithRetryPolicy(policy),
+	)
+
+	var attempts int
+	mux.HandleFunc("/applications/app-1", func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusBadGateway)
+	})
+
+	_, resp, err := client.Applications.Get(context.Background(), "app-1")
+	if !IsServerError(err) {
+		t.Fatalf("Expected server error, got %v", err)
+	}
+
+	if resp.StatusCode != http.StatusBadGateway {
+		t.Errorf("Expected status 502, got %d", resp.StatusCode)
+	}
+
+	if attempts != 2 {
+		t.Errorf("Expected 2 attempts, got %d", attempts)
+	}
+}
+
+func TestClient_DoRetryRespectsContextDeadline(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	policy := DefaultRetryPolicy()
+	policy.MaxAttempts = 5
+	policy.BaseDelay = time.Second
+
+	client := NewClient(
+		WithAPIKey("test-key"),
+		WithBaseURL(server.URL),
+		WithRetryPolicy(policy),
+	)
+
+	var attempts int
+	mux.HandleFunc("/applications/app-1", func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, _, err := client.Applications.Get(ctx, "app-1")
+	if err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Expected retry to stop before the deadline, took %v", elapsed)
+	}
+
+	if attempts != 1 {
+		t.Errorf("Expected 1 attempt, got %d", attempts)
+	}
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := &RetryPolicy{
+		BaseDelay: 100 * time.Millisecond,
+		MaxDelay:  time.Second,
+	}
+
+	tests := []struct {
+		retry int
+		want  time.Duration
+	}{
+		{1, 100 * time.Millisecond},
+		{2, 200 * time.Millisecond},
+		{3, 400 * time.Millisecond},
+		{4, 800 * time.Millisecond},
+		{5, time.Second},
+		{10, time.Second},
+	}
+
+	for _, tt := range tests {
+		if got := policy.backoff(tt.retry); got != tt.want {
+			t.Errorf("backoff(%d) = %v, want %v", tt.retry, got, tt.want)
 		}
 	}
-	return false
+
+	policy.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		got := policy.backoff(2)
+		if got < 100*time.Millisecond || got > 200*time.Millisecond {
+			t.Fatalf("backoff(2) with jitter = %v, want between 100ms and 200ms", got)
+		}
+	}
+}
+
+// Rate Limit Tests
+
+func TestResponse_PopulateRateValues(t *testing.T) {
+	reset := time.Now().Add(time.Minute).Truncate(time.Second)
+
+	resp := &Response{
+		Response: &http.Response{
+			Header: http.Header{},
+		},
+	}
+	resp.Header.Set("X-RateLimit-Limit", "100")
+	resp.Header.Set("X-RateLimit-Remaining", "42")
+	resp.Header.Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+
+	resp.populateRateValues()
+
+	if resp.Rate.Limit != 100 {
+		t.Errorf("Rate.Limit = %d, want 100", resp.Rate.Limit)
+	}
+	if resp.Rate.Remaining != 42 {
+		t.Errorf("Rate.Remaining = %d, want 42", resp.Rate.Remaining)
+	}
+	if !resp.Rate.Reset.Equal(reset) {
+		t.Errorf("Rate.Reset = %v, want %v", resp.Rate.Reset, reset)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		value string
+		want  time.Duration
+	}{
+		{"delta seconds", "120", 2 * time.Minute},
+		{"http date", now.Add(30 * time.Second).Format(http.TimeFormat), 30 * time.Second},
+		{"date in the past", now.Add(-time.Minute).Format(http.TimeFormat), 0},
+		{"negative seconds", "-5", 0},
+		{"invalid", "soon", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseRetryAfter(tt.value, now); got != tt.want {
+				t.Errorf("parseRetryAfter(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckResponse_RateLimited(t *testing.T) {
+	resp := &http.Response{
+		StatusCode: http.StatusTooManyRequests,
+		Header:     http.Header{},
+		Body:       io.NopCloser(bytes.NewBufferString(`{"message": "Too many requests"}`)),
+	}
+	resp.Header.Set("Retry-After", "30")
+	resp.Header.Set("X-RateLimit-Limit", "60")
+	resp.Header.Set("X-RateLimit-Remaining", "0")
+
+	err := CheckResponse(resp)
+
+	rateErr, ok := err.(*RateLimitError)
+	if !ok {
+		t.Fatalf("Expected RateLimitError, got %T", err)
+	}
+
+	if rateErr.RetryAfter != 30 {
+		t.Errorf("RetryAfter = %d, want 30", rateErr.RetryAfter)
+	}
+	if rateErr.Rate.Limit != 60 {
+		t.Errorf("Rate.Limit = %d, want 60", rateErr.Rate.Limit)
+	}
+	if rateErr.Message != "Too many requests" {
+		t.Errorf("Message = %q, want 'Too many requests'", rateErr.Message)
+	}
+
+	if !IsRateLimited(err) {
+		t.Error("Expected IsRateLimited to recognise RateLimitError")
+	}
+	if !IsClientError(err) {
+		t.Error("Expected IsClientError to recognise RateLimitError")
+	}
+}
+
+func TestClient_DoRateLimitedPopulatesRate(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := NewClient(
+		WithAPIKey("test-key"),
+		WithBaseURL(server.URL),
+	)
+
+	mux.HandleFunc("/applications", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "60")
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("Retry-After", "12")
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+
+	_, resp, err := client.Applications.List(context.Background(), nil)
+	if !IsRateLimited(err) {
+		t.Fatalf("Expected rate limit error, got %v", err)
+	}
+
+	if resp.Rate.Limit != 60 || resp.Rate.Remaining != 0 {
+		t.Errorf("Rate = %+v, want Limit 60 and Remaining 0", resp.Rate)
+	}
+	if resp.Rate.RetryAfter != 12*time.Second {
+		t.Errorf("Rate.RetryAfter = %v, want 12s", resp.Rate.RetryAfter)
+	}
+}
+
+// Rate Limiter Tests
+
+func TestRateLimiter_Burst(t *testing.T) {
+	limiter := NewRateLimiter(10, 2)
+	ctx := context.Background()
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := limiter.Wait(ctx); err != nil {
+			t.Fatalf("Wait returned error: %v", err)
+		}
+	}
+
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("Expected third request to be paced, took %v", elapsed)
+	}
+}
+
+func TestRateLimiter_ServerQuotaExhausted(t *testing.T) {
+	limiter := NewRateLimiter(0, 1)
+
+	limiter.Update(Rate{
+		Limit:     60,
+		Remaining: 0,
+		Reset:     time.Now().Add(time.Minute),
+		HasQuota:  true,
+	})
+
+	if wait := limiter.WaitTime(); wait < 55*time.Second {
+		t.Errorf("WaitTime = %v, want close to 1m", wait)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := limiter.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Wait error = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestRateLimiter_AdjustsToServerQuota(t *testing.T) {
+	limiter := NewRateLimiter(0, 5)
+
+	limiter.Update(Rate{
+		Limit:     100,
+		Remaining: 1,
+		Reset:     time.Now().Add(10 * time.Second),
+		HasQuota:  true,
+	})
+
+	if err := limiter.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait returned error: %v", err)
+	}
+
+	if wait := limiter.WaitTime(); wait < 5*time.Second {
+		t.Errorf("WaitTime = %v, want the remaining quota spread until reset", wait)
+	}
+}
+
+func TestRateLimiter_UpdateWithoutLimit(t *testing.T) {
+	limiter := NewRateLimiter(0, 1)
+
+	limiter.Update(Rate{Remaining: 0, Reset: time.Now().Add(time.Minute), HasQuota: true})
+
+	if wait := limiter.WaitTime(); wait < 55*time.Second {
+		t.Errorf("WaitTime = %v, want close to 1m without Limit", wait)
+	}
+}
+
+func TestRateLimiter_UpdateWithoutRemaining(t *testing.T) {
+	limiter := NewRateLimiter(0, 1)
+
+	// A reset time without the remaining requests is not a quota
+	resp := &http.Response{Header: http.Header{}}
+	resp.Header.Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10))
+	rate := parseRate(resp)
+	if rate.HasQuota {
+		t.Errorf("Expected HasQuota to be false without X-RateLimit-Remaining, got %+v", rate)
+	}
+
+	limiter.Update(rate)
+	if wait := limiter.WaitTime(); wait != 0 {
+		t.Errorf("WaitTime = %v, want 0 for a Reset without Remaining", wait)
+	}
+
+	// Retry-After fills in Reset but still only blocks for its own delay
+	resp.Header.Set("Retry-After", "1")
+	limiter.Update(parseRate(resp))
+	if wait := limiter.WaitTime(); wait > time.Second {
+		t.Errorf("WaitTime = %v, want at most the Retry-After delay", wait)
+	}
+
+	limiter.Update(Rate{Reset: time.Now().Add(time.Hour)})
+	if wait := limiter.WaitTime(); wait > time.Second {
+		t.Errorf("WaitTime = %v, want a Rate without HasQuota ignored", wait)
+	}
+}
+
+func TestRateLimiter_Concurrent(t *testing.T) {
+	limiter := NewRateLimiter(1000, 10)
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := limiter.Wait(ctx); err != nil {
+				t.Errorf("Wait returned error: %v", err)
+			}
+			limiter.Update(Rate{Limit: 100, Remaining: 50, Reset: time.Now().Add(time.Second), HasQuota: true})
+		}()
+	}
+	wg.Wait()
+}
+
+func TestClient_DoWithRateLimiter(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	limiter := NewRateLimiter(0, 1)
+	client := NewClient(
+		WithAPIKey("test-key"),
+		WithBaseURL(server.URL),
+		WithRateLimiter(limiter),
+	)
+
+	var requests int
+	mux.HandleFunc("/applications", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("X-RateLimit-Limit", "60")
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[]`))
+	})
+
+	if _, _, err := client.Applications.List(context.Background(), nil); err != nil {
+		t.Fatalf("Applications.List returned error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if _, _, err := client.Applications.List(ctx, nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+
+	if requests != 1 {
+		t.Errorf("Expected 1 request to reach the server, got %d", requests)
+	}
+}
+
+func TestClient_DoWithRateLimiterWithoutLimitHeader(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	limiter := NewRateLimiter(0, 1)
+	client := NewClient(
+		WithAPIKey("test-key"),
+		WithBaseURL(server.URL),
+		WithRateLimiter(limiter),
+	)
+
+	var requests int
+	mux.HandleFunc("/applications", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		// Only the remaining requests and reset time are reported
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[]`))
+	})
+	mux.HandleFunc("/databases", func(w http.ResponseWriter, r *http.Request) {
+		// A reset time without the remaining requests says nothing about the quota
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[]`))
+	})
+
+	if _, _, err := client.Databases.List(context.Background(), nil); err != nil {
+		t.Fatalf("Databases.List returned error: %v", err)
+	}
+	if wait := limiter.WaitTime(); wait != 0 {
+		t.Errorf("Expected no wait without X-RateLimit-Remaining, got %v", wait)
+	}
+
+	if _, _, err := client.Applications.List(context.Background(), nil); err != nil {
+		t.Fatalf("Applications.List returned error: %v", err)
+	}
+	if wait := limiter.WaitTime(); wait < 59*time.Minute {
+		t.Errorf("Expected to wait for the reset without X-RateLimit-Limit, got %v", wait)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if _, _, err := client.Applications.List(ctx, nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("Expected 1 request to reach the server, got %d", requests)
+	}
+}
+
+// Pagination Iterator Tests
+
+func TestApplicationsService_ListAll(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := NewClient(
+		WithAPIKey("test-key"),
+		WithBaseURL(server.URL),
+	)
+
+	var requests int
+	mux.HandleFunc("/applications", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+
+		page := r.URL.Query().Get("page")
+		if r.URL.Query().Get("per_page") != "2" {
+			t.Errorf("Expected per_page=2, got %s", r.URL.Query().Get("per_page"))
+		}
+
+		var apps []*Application
+		switch page {
+		case "", "1":
+			w.Header().Set("Link", `<`+server.URL+`/applications?page=2>; rel="next"`)
+			apps = []*Application{{ID: "app-1"}, {ID: "app-2"}}
+		case "2":
+			w.Header().Set("Link", `<`+server.URL+`/applications?page=3>; rel="next"`)
+			apps = []*Application{{ID: "app-3"}, {ID: "app-4"}}
+		case "3":
+			apps = []*Application{{ID: "app-5"}}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(apps)
+	})
+
+	opts := &ListOptions{PerPage: 2}
+	apps, err := client.Applications.ListAll(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("Applications.ListAll returned error: %v", err)
+	}
+
+	if len(apps) != 5 {
+		t.Fatalf("Expected 5 applications, got %d", len(apps))
+	}
+	if apps[4].ID != "app-5" {
+		t.Errorf("Expected last application 'app-5', got %s", apps[4].ID)
+	}
+	if requests != 3 {
+		t.Errorf("Expected 3 requests, got %d", requests)
+	}
+	if opts.Page != 0 {
+		t.Errorf("Expected caller's ListOptions to be left untouched, got page %d", opts.Page)
+	}
+
+	// MaxItems stops fetching once the cap is reached
+	requests = 0
+	apps, err = client.Applications.ListAll(context.Background(), &ListOptions{PerPage: 2, MaxItems: 3})
+	if err != nil {
+		t.Fatalf("Applications.ListAll returned error: %v", err)
+	}
+	if len(apps) != 3 {
+		t.Errorf("Expected 3 applications, got %d", len(apps))
+	}
+	if requests != 2 {
+		t.Errorf("Expected 2 requests, got %d", requests)
+	}
+}
+
+func TestApplicationsService_ListAllStartsAtPageOne(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+
+	var pages []string
+	mux.HandleFunc("/applications", func(w http.ResponseWriter, r *http.Request) {
+		pages = append(pages, r.URL.Query().Get("page"))
+
+		// The first page links to itself, as an API counting from 1 would
+		// for a request without a page
+		w.Header().Set("Link", `<`+server.URL+`/applications?page=1>; rel="next"`)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode([]*Application{{ID: "app-1"}, {ID: "app-2"}})
+	})
+
+	apps, err := client.Applications.ListAll(context.Background(), &ListOptions{PerPage: 2})
+	if err != nil {
+		t.Fatalf("Applications.ListAll returned error: %v", err)
+	}
+
+	if len(apps) != 2 {
+		t.Errorf("Expected 2 applications, got %d", len(apps))
+	}
+	if !reflect.DeepEqual(pages, []string{"1"}) {
+		t.Errorf("Expected a single request for page 1, got pages %q", pages)
+	}
+}
+
+func TestPipelinesService_ListRunsIter(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := NewClient(
+		WithAPIKey("test-key"),
+		WithBaseURL(server.URL),
+	)
+
+	mux.HandleFunc("/pipelines/pipe-1/runs", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == "2" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Link", `<`+server.URL+`/pipelines/pipe-1/runs?page=2>; rel="next"`)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode([]*PipelineRun{{ID: "run-1"}, {ID: "run-2"}})
+	})
+
+	var ids []string
+	var iterErr error
+	for run, err := range client.Pipelines.ListRunsIter(context.Background(), "pipe-1", nil) {
+		if err != nil {
+			iterErr = err
+			break
+		}
+		ids = append(ids, run.ID)
+	}
+
+	if !reflect.DeepEqual(ids, []string{"run-1", "run-2"}) {
+		t.Errorf("Expected runs [run-1 run-2], got %v", ids)
+	}
+	if !IsServerError(iterErr) {
+		t.Errorf("Expected server error from second page, got %v", iterErr)
+	}
+
+	// Breaking out of the loop stops further requests
+	for run, err := range client.Pipelines.ListRunsIter(context.Background(), "pipe-1", nil) {
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if run.ID == "run-1" {
+			break
+		}
+	}
+}
+
+// Waiter Tests
+
+func TestDeploymentsService_WaitForDeployment(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := NewClient(
+		WithAPIKey("test-key"),
+		WithBaseURL(server.URL),
+	)
+
+	states := []Status{StatusQueued, StatusBuilding, StatusBuilding, StatusDeploying, StatusSuccess}
+	var polls int
+	mux.HandleFunc("/deployments/deploy-1", func(w http.ResponseWriter, r *http.Request) {
+		state := states[polls]
+		polls++
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(&Deployment{ID: "deploy-1", State: state})
+	})
+
+	var transitions []Status
+	deployment, err := client.Deployments.WaitForDeployment(context.Background(), "deploy-1", &WaitOptions[*Deployment]{
+		PollInterval: time.Millisecond,
+		Multiplier:   2,
+		OnTransition: func(d *Deployment) {
+			transitions = append(transitions, d.State)
+		},
+	})
+	if err != nil {
+		t.Fatalf("WaitForDeployment returned error: %v", err)
+	}
+
+	if deployment.State != StatusSuccess {
+		t.Errorf("Expected state %s, got %s", StatusSuccess, deployment.State)
+	}
+
+	want := []Status{StatusQueued, StatusBuilding, StatusDeploying, StatusSuccess}
+	if !reflect.DeepEqual(transitions, want) {
+		t.Errorf("Expected transitions %v, got %v", want, transitions)
+	}
+
+	if polls != len(states) {
+		t.Errorf("Expected %d polls, got %d", len(states), polls)
+	}
+}
+
+func TestDeploymentsService_WaitForDeploymentTemporaryErrors(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := NewClient(
+		WithAPIKey("test-key"),
+		WithBaseURL(server.URL),
+	)
+
+	var polls int
+	mux.HandleFunc("/deployments/deploy-1", func(w http.ResponseWriter, r *http.Request) {
+		polls++
+		w.Header().Set("Content-Type", "application/json")
+		switch polls {
+		case 1:
+			_ = json.NewEncoder(w).Encode(&Deployment{ID: "deploy-1", State: StatusBuilding})
+		case 2:
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(`{"message":"Unavailable"}`))
+		default:
+			_ = json.NewEncoder(w).Encode(&Deployment{ID: "deploy-1", State: StatusSuccess})
+		}
+	})
+
+	deployment, err := client.Deployments.WaitForDeployment(context.Background(), "deploy-1", &WaitOptions[*Deployment]{
+		PollInterval: time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("WaitForDeployment returned error: %v", err)
+	}
+	if deployment.State != StatusSuccess || polls != 3 {
+		t.Errorf("Expected success after 3 polls, got %s after %d", deployment.State, polls)
+	}
+
+	// Permanent errors and too many temporary errors end the wait
+	mux.HandleFunc("/deployments/missing", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	if _, err := client.Deployments.WaitForDeployment(context.Background(), "missing", &WaitOptions[*Deployment]{
+		PollInterval: time.Millisecond,
+	}); !IsNotFound(err) {
+		t.Errorf("Expected not found error, got %v", err)
+	}
+
+	var unavailable int
+	mux.HandleFunc("/deployments/down", func(w http.ResponseWriter, r *http.Request) {
+		unavailable++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	if _, err := client.Deployments.WaitForDeployment(context.Background(), "down", &WaitOptions[*Deployment]{
+		PollInterval:   time.Millisecond,
+		MaxFetchErrors: 2,
+	}); !IsServerError(err) || unavailable != 3 {
+		t.Errorf("Expected a server error after 3 polls, got %v after %d", err, unavailable)
+	}
+}
+
+func TestDeploymentsService_WaitForDeploymentFailed(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := NewClient(
+		WithAPIKey("test-key"),
+		WithBaseURL(server.URL),
+	)
+
+	mux.HandleFunc("/deployments/deploy-1", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(&Deployment{
+			ID:           "deploy-1",
+			State:        StatusFailed,
+			ErrorMessage: "build failed",
+			BuildLogs:    "npm ERR! missing script: build",
+		})
+	})
+
+	deployment, err := client.Deployments.WaitForDeployment(context.Background(), "deploy-1", nil)
+
+	deployErr, ok := err.(*DeploymentError)
+	if !ok {
+		t.Fatalf("Expected DeploymentError, got %T", err)
+	}
+
+	if deployErr.Deployment.BuildLogs != "npm ERR! missing script: build" {
+		t.Errorf("Expected build logs on error, got %q", deployErr.Deployment.BuildLogs)
+	}
+
+	if !contains(err.Error(), "build failed") {
+		t.Errorf("Expected error to contain error message, got %s", err.Error())
+	}
+
+	if deployment == nil || deployment.State != StatusFailed {
+		t.Errorf("Expected final failed deployment, got %+v", deployment)
+	}
+}
+
+func TestDeploymentsService_WaitForDeploymentContextCancelled(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := NewClient(
+		WithAPIKey("test-key"),
+		WithBaseURL(server.URL),
+	)
+
+	mux.HandleFunc("/deployments/deploy-1", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(&Deployment{ID: "deploy-1", State: StatusBuilding})
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	deployment, err := client.Deployments.WaitForDeployment(ctx, "deploy-1", &WaitOptions[*Deployment]{
+		PollInterval: 10 * time.Millisecond,
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected context.DeadlineExceeded, got %v", err)
+	}
+
+	if deployment == nil || deployment.State != StatusBuilding {
+		t.Errorf("Expected last seen deployment, got %+v", deployment)
+	}
+}
+
+func TestApplicationsService_WaitForState(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := NewClient(
+		WithAPIKey("test-key"),
+		WithBaseURL(server.URL),
+	)
+
+	states := []ApplicationState{StateStopped, StatePending, StateRunning}
+	var polls int
+	mux.HandleFunc("/applications/app-1", func(w http.ResponseWriter, r *http.Request) {
+		state := states[polls]
+		polls++
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(&Application{ID: "app-1", State: state})
+	})
+
+	app, err := client.Applications.WaitForState(context.Background(), "app-1", StateRunning, &WaitOptions[*Application]{
+		PollInterval: time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("WaitForState returned error: %v", err)
+	}
+
+	if app.State != StateRunning {
+		t.Errorf("Expected state %s, got %s", StateRunning, app.State)
+	}
+	if polls != 3 {
+		t.Errorf("Expected 3 polls, got %d", polls)
+	}
+}
+
+func TestApplicationsService_WaitForStateFailed(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := NewClient(
+		WithAPIKey("test-key"),
+		WithBaseURL(server.URL),
+	)
+
+	mux.HandleFunc("/applications/app-1", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(&Application{ID: "app-1", State: StateFailed})
+	})
+
+	_, err := client.Applications.WaitForState(context.Background(), "app-1", StateRunning, nil)
+
+	stateErr, ok := err.(*StateError)
+	if !ok {
+		t.Fatalf("Expected StateError, got %T", err)
+	}
+	if stateErr.State != string(StateFailed) {
+		t.Errorf("Expected state %s, got %s", StateFailed, stateErr.State)
+	}
+}
+
+func TestDatabasesService_WaitForBackup(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := NewClient(
+		WithAPIKey("test-key"),
+		WithBaseURL(server.URL),
+	)
+
+	statuses := []string{BackupStatusPending, BackupStatusInProgress, BackupStatusCompleted}
+	var polls int
+	mux.HandleFunc("/databases/db-1/backups/backup-1", func(w http.ResponseWriter, r *http.Request) {
+		status := statuses[polls]
+		polls++
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(&Backup{ID: "backup-1", DatabaseID: "db-1", Status: status})
+	})
+
+	backup, err := client.Databases.WaitForBackup(context.Background(), "db-1", "backup-1", &WaitOptions[*Backup]{
+		PollInterval: time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("WaitForBackup returned error: %v", err)
+	}
+
+	if backup.Status != BackupStatusCompleted {
+		t.Errorf("Expected status %s, got %s", BackupStatusCompleted, backup.Status)
+	}
+}
+
+func TestDatabasesService_WaitUntilReadyTimeout(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := NewClient(
+		WithAPIKey("test-key"),
+		WithBaseURL(server.URL),
+	)
+
+	mux.HandleFunc("/databases/db-1", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(&Database{ID: "db-1"})
+	})
+
+	_, err := client.Databases.WaitUntilReady(context.Background(), "db-1", &WaitOptions[*Database]{
+		PollInterval: 5 * time.Millisecond,
+		Timeout:      30 * time.Millisecond,
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestPipelinesService_WaitForRunFailed(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := NewClient(
+		WithAPIKey("test-key"),
+		WithBaseURL(server.URL),
+	)
+
+	mux.HandleFunc("/pipelines/pipe-1/runs/run-1", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(&PipelineRun{
+			ID:    "run-1",
+			State: StatusFailed,
+			Steps: []PipelineRunStep{
+				{Name: "build", State: StatusSuccess},
+				{Name: "test", State: StatusFailed, ErrorMessage: "2 tests failed"},
+			},
+		})
+	})
+
+	run, err := client.Pipelines.WaitForRun(context.Background(), "pipe-1", "run-1", nil)
+	if run == nil || run.State != StatusFailed {
+		t.Errorf("Expected final failed run, got %+v", run)
+	}
+
+	stateErr, ok := err.(*StateError)
+	if !ok {
+		t.Fatalf("Expected StateError, got %T", err)
+	}
+	if stateErr.Message != "test: 2 tests failed" {
+		t.Errorf("Expected failed step message, got %q", stateErr.Message)
+	}
+}
+
+// Log Streaming Tests
+
+func TestApplicationsService_TailLogsSSE(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := NewClient(
+		WithAPIKey("test-key"),
+		WithBaseURL(server.URL),
+	)
+
+	mux.HandleFunc("/applications/app-1/logs/stream", func(w http.ResponseWriter, r *http.Request) {
+		if accept := r.Header.Get("Accept"); !contains(accept, "text/event-stream") {
+			t.Errorf("Expected Accept header to contain text/event-stream, got %s", accept)
+		}
+		if since := r.URL.Query().Get("since"); since != "2025-10-18T12:00:00Z" {
+			t.Errorf("Expected since=2025-10-18T12:00:00Z, got %s", since)
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = io.WriteString(w, ": keep-alive\n\n")
+		_, _ = io.WriteString(w, "id: 1\ndata: {\"timestamp\":\"2025-10-18T12:00:01Z\",\"stream\":\"stdout\",\"message\":\"listening on :8080\"}\n\n")
+		_, _ = io.WriteString(w, "id: 2\ndata: 2025-10-18T12:00:02Z stderr warning: low memory\n\n")
+		_, _ = io.WriteString(w, "event: end\ndata: {}\n\n")
+	})
+
+	stream, err := client.Applications.TailLogs(context.Background(), "app-1", &TailLogsOptions{
+		Since: time.Date(2025, 10, 18, 12, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("TailLogs returned error: %v", err)
+	}
+	defer stream.Close()
+
+	var lines []LogLine
+	for line := range stream.Lines() {
+		lines = append(lines, line)
+	}
+
+	if err := stream.Err(); err != nil {
+		t.Fatalf("Stream ended with error: %v", err)
+	}
+
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines, got %d", len(lines))
+	}
+
+	want := LogLine{
+		Timestamp: time.Date(2025, 10, 18, 12, 0, 1, 0, time.UTC),
+		Stream:    "stdout",
+		Message:   "listening on :8080",
+		Cursor:    "1",
+	}
+	if !reflect.DeepEqual(lines[0], want) {
+		t.Errorf("First line = %+v, want %+v", lines[0], want)
+	}
+
+	if lines[1].Stream != "stderr" || lines[1].Message != "warning: low memory" || lines[1].Cursor != "2" {
+		t.Errorf("Unexpected second line: %+v", lines[1])
+	}
+}
+
+func TestDeploymentsService_TailLogsResumesAfterDisconnect(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := NewClient(
+		WithAPIKey("test-key"),
+		WithBaseURL(server.URL),
+	)
+
+	var (
+		mu       sync.Mutex
+		requests int
+		cursors  []string
+	)
+	mux.HandleFunc("/deployments/deploy-1/logs/stream", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests++
+		attempt := requests
+		cursors = append(cursors, r.Header.Get("Last-Event-ID"))
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		if attempt == 1 {
+			_, _ = io.WriteString(w, "id: c1\ndata: step 1\n\n")
+			w.(http.Flusher).Flush()
+			panic(http.ErrAbortHandler)
+		}
+
+		_, _ = io.WriteString(w, "id: c2\ndata: step 2\n\nevent: end\n\n")
+	})
+
+	stream, err := client.Deployments.TailLogs(context.Background(), "deploy-1", &TailLogsOptions{
+		ReconnectDelay: time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("TailLogs returned error: %v", err)
+	}
+	defer stream.Close()
+
+	var messages []string
+	for line := range stream.Lines() {
+		messages = append(messages, line.Message)
+	}
+
+	if err := stream.Err(); err != nil {
+		t.Fatalf("Stream ended with error: %v", err)
+	}
+
+	if !reflect.DeepEqual(messages, []string{"step 1", "step 2"}) {
+		t.Errorf("Expected messages [step 1 step 2], got %v", messages)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if !reflect.DeepEqual(cursors, []string{"", "c1"}) {
+		t.Errorf("Expected Last-Event-ID headers [\"\" c1], got %q", cursors)
+	}
+}
+
+func TestApplicationsService_TailLogsResumesWithoutCursor(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := NewClient(
+		WithAPIKey("test-key"),
+		WithBaseURL(server.URL),
+	)
+
+	lines := []string{
+		`{"timestamp":"2025-10-18T12:00:01Z","message":"one"}`,
+		`{"timestamp":"2025-10-18T12:00:02Z","message":"two"}`,
+		`{"timestamp":"2025-10-18T12:00:02Z","message":"two again"}`,
+		`{"timestamp":"2025-10-18T12:00:03Z","message":"three"}`,
+	}
+
+	var (
+		mu    sync.Mutex
+		since []string
+	)
+	mux.HandleFunc("/applications/app-1/logs/stream", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		since = append(since, r.URL.Query().Get("since"))
+		attempt := len(since)
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		if attempt == 1 {
+			for _, line := range lines[:3] {
+				_, _ = io.WriteString(w, line+"\n")
+			}
+			w.(http.Flusher).Flush()
+			panic(http.ErrAbortHandler)
+		}
+
+		// Since is inclusive, so the lines logged at 12:00:02 are sent again
+		for _, line := range lines[1:] {
+			_, _ = io.WriteString(w, line+"\n")
+		}
+	})
+
+	stream, err := client.Applications.TailLogs(context.Background(), "app-1", &TailLogsOptions{
+		ReconnectDelay: time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("TailLogs returned error: %v", err)
+	}
+	defer stream.Close()
+
+	var messages []string
+	for line := range stream.Lines() {
+		messages = append(messages, line.Message)
+	}
+
+	if err := stream.Err(); err != nil {
+		t.Fatalf("Stream ended with error: %v", err)
+	}
+	if !reflect.DeepEqual(messages, []string{"one", "two", "two again", "three"}) {
+		t.Errorf("Expected every line once, got %q", messages)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if !reflect.DeepEqual(since, []string{"", "2025-10-18T12:00:02Z"}) {
+		t.Errorf("Expected a resume from the last timestamp, got since %q", since)
+	}
+}
+
+func TestApplicationsService_TailLogsReconnectsAfterEOFWithoutEnd(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := NewClient(
+		WithAPIKey("test-key"),
+		WithBaseURL(server.URL),
+	)
+
+	var (
+		mu      sync.Mutex
+		cursors []string
+	)
+	mux.HandleFunc("/applications/app-1/logs/stream", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		cursors = append(cursors, r.Header.Get("Last-Event-ID"))
+		attempt := len(cursors)
+		mu.Unlock()
+
+		// The first response is closed cleanly before the end event
+		w.Header().Set("Content-Type", "text/event-stream")
+		if attempt == 1 {
+			_, _ = io.WriteString(w, "id: c1\ndata: line 1\n\n")
+			return
+		}
+
+		_, _ = io.WriteString(w, "id: c2\ndata: line 2\n\nevent: end\ndata: {}\n\n")
+	})
+
+	stream, err := client.Applications.TailLogs(context.Background(), "app-1", &TailLogsOptions{
+		ReconnectDelay: time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("TailLogs returned error: %v", err)
+	}
+	defer stream.Close()
+
+	var messages []string
+	for line := range stream.Lines() {
+		messages = append(messages, line.Message)
+	}
+
+	if err := stream.Err(); err != nil {
+		t.Fatalf("Stream ended with error: %v", err)
+	}
+	if !reflect.DeepEqual(messages, []string{"line 1", "line 2"}) {
+		t.Errorf("Expected messages [line 1 line 2], got %v", messages)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if !reflect.DeepEqual(cursors, []string{"", "c1"}) {
+		t.Errorf("Expected a reconnect resuming after c1, got Last-Event-ID headers %q", cursors)
+	}
+}
+
+func TestApplicationsService_TailLogsEOFWithoutEndExhaustsReconnects(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := NewClient(
+		WithAPIKey("test-key"),
+		WithBaseURL(server.URL),
+	)
+
+	mux.HandleFunc("/applications/app-1/logs/stream", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = io.WriteString(w, ": keep-alive\n\n")
+	})
+
+	stream, err := client.Applications.TailLogs(context.Background(), "app-1", &TailLogsOptions{
+		MaxReconnects:  2,
+		ReconnectDelay: time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("TailLogs returned error: %v", err)
+	}
+	defer stream.Close()
+
+	for range stream.Lines() {
+	}
+
+	if err := stream.Err(); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("Expected io.ErrUnexpectedEOF after the reconnects, got %v", err)
+	}
+}
+
+func TestDeploymentsService_TailLogsNotFound(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := NewClient(
+		WithAPIKey("test-key"),
+		WithBaseURL(server.URL),
+	)
+
+	mux.HandleFunc("/deployments/missing/logs/stream", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	_, err := client.Deployments.TailLogs(context.Background(), "missing", nil)
+	if !IsNotFound(err) {
+		t.Errorf("Expected not found error, got %v", err)
+	}
+}
+
+func TestApplicationsService_TailLogsThroughMiddleware(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	var connects atomic.Int32
+	signing := func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request, v interface{}) (*Response, error) {
+			connects.Add(1)
+			req.Header.Set("X-Signature", "signed")
+			return next.Do(req, v)
+		})
+	}
+
+	client := NewClient(
+		WithAPIKey("test-key"),
+		WithBaseURL(server.URL),
+		WithMiddleware(signing),
+		WithRetryPolicy(&RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond}),
+		WithTimeout(20*time.Millisecond),
+	)
+
+	var requests atomic.Int32
+	mux.HandleFunc("/applications/app-1/logs/stream", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Signature") != "signed" {
+			t.Errorf("Expected the middleware's header, got %q", r.Header.Get("X-Signature"))
+		}
+		if requests.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		_, _ = io.WriteString(w, `{"message":"first"}`+"\n")
+		w.(http.Flusher).Flush()
+		time.Sleep(50 * time.Millisecond)
+		_, _ = io.WriteString(w, `{"message":"second"}`+"\n")
+	})
+
+	stream, err := client.Applications.TailLogs(context.Background(), "app-1", &TailLogsOptions{MaxReconnects: -1})
+	if err != nil {
+		t.Fatalf("TailLogs returned error: %v", err)
+	}
+	defer stream.Close()
+
+	var messages []string
+	for line := range stream.Lines() {
+		messages = append(messages, line.Message)
+	}
+
+	if err := stream.Err(); err != nil {
+		t.Fatalf("Expected the client timeout not to end the stream, got %v", err)
+	}
+	if !reflect.DeepEqual(messages, []string{"first", "second"}) {
+		t.Errorf("Expected messages [first second], got %v", messages)
+	}
+	if connects.Load() != 1 || requests.Load() != 2 {
+		t.Errorf("Expected 1 call through the middleware retried once, got %d calls and %d requests", connects.Load(), requests.Load())
+	}
+}
+
+func TestLogStream_Close(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := NewClient(
+		WithAPIKey("test-key"),
+		WithBaseURL(server.URL),
+	)
+
+	mux.HandleFunc("/applications/app-1/logs/stream", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		_, _ = io.WriteString(w, `{"message":"first"}`+"\n")
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	})
+
+	stream, err := client.Applications.TailLogs(context.Background(), "app-1", nil)
+	if err != nil {
+		t.Fatalf("TailLogs returned error: %v", err)
+	}
+
+	line := <-stream.Lines()
+	if line.Message != "first" {
+		t.Errorf("Expected message 'first', got %q", line.Message)
+	}
+
+	if err := stream.Close(); err != nil {
+		t.Errorf("Close returned error: %v", err)
+	}
+
+	if _, ok := <-stream.Lines(); ok {
+		t.Error("Expected lines channel to be closed")
+	}
+	if err := stream.Err(); err != nil {
+		t.Errorf("Expected no error after Close, got %v", err)
+	}
+}
+
+// Helper function
+func contains(s, substr string) bool {
+	return len(s) >= len(substr) && (s == substr || len(s) > len(substr) && (s[:len(substr)] == substr || s[len(s)-len(substr):] == substr || containsMiddle(s, substr)))
+}
+
+func containsMiddle(s, substr string) bool {
+	for i := 0; i <= len(s)-len(substr); i++ {
+		if s[i:i+len(substr)] == substr {
+			return true
+		}
+	}
+	return false
+}
+
+func TestClient_DoMiddlewareOrder(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	var calls []string
+	record := func(name string) Middleware {
+		return func(next Doer) Doer {
+			return DoerFunc(func(req *http.Request, v interface{}) (*Response, error) {
+				calls = append(calls, name+" before")
+				req.Header.Add("X-Trace", name)
+				resp, err := next.Do(req, v)
+				calls = append(calls, name+" after")
+				return resp, err
+			})
+		}
+	}
+
+	client := NewClient(
+		WithBaseURL(server.URL),
+		WithMiddleware(record("first")),
+		WithMiddleware(record("second")),
+	)
+
+	mux.HandleFunc("/applications/app-1", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Values("X-Trace"); !reflect.DeepEqual(got, []string{"first", "second"}) {
+			t.Errorf("Expected X-Trace [first second], got %v", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(&Application{ID: "app-1"})
+	})
+
+	if _, _, err := client.Applications.Get(context.Background(), "app-1"); err != nil {
+		t.Fatalf("Applications.Get returned error: %v", err)
+	}
+
+	want := []string{"first before", "second before", "second after", "first after"}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("Expected calls %v, got %v", want, calls)
+	}
+}
+
+func TestClient_DoMiddlewareSeesDecodedError(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	var calls int
+	var gotStatus int
+	var gotErr error
+	client := NewClient(
+		WithBaseURL(server.URL),
+		WithRetryPolicy(&RetryPolicy{
+			MaxAttempts:          2,
+			BaseDelay:            time.Millisecond,
+			RetryableStatusCodes: []int{http.StatusServiceUnavailable},
+		}),
+		WithMiddleware(func(next Doer) Doer {
+			return DoerFunc(func(req *http.Request, v interface{}) (*Response, error) {
+				calls++
+				resp, err := next.Do(req, v)
+				gotStatus, gotErr = resp.StatusCode, err
+				return resp, err
+			})
+		}),
+	)
+
+	var attempts int
+	mux.HandleFunc("/applications/app-1", func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte(`{"message":"Unavailable","code":"unavailable"}`))
+	})
+
+	_, _, err := client.Applications.Get(context.Background(), "app-1")
+	if err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+
+	if attempts != 2 || calls != 1 {
+		t.Errorf("Expected 2 attempts inside 1 middleware call, got %d attempts and %d calls", attempts, calls)
+	}
+
+	if gotStatus != http.StatusServiceUnavailable {
+		t.Errorf("Expected middleware to see status 503, got %d", gotStatus)
+	}
+
+	var errResp *ErrorResponse
+	if !errors.As(gotErr, &errResp) || errResp.Code != "unavailable" {
+		t.Errorf("Expected middleware to see *ErrorResponse with code 'unavailable', got %v", gotErr)
+	}
+}
+
+func TestClient_WithLogger(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	client := NewClient(
+		WithAPIKey("secret-key"),
+		WithBaseURL(server.URL),
+		WithLogger(logger),
+		WithRetryPolicy(&RetryPolicy{
+			MaxAttempts:          2,
+			BaseDelay:            time.Millisecond,
+			RetryableStatusCodes: []int{http.StatusServiceUnavailable},
+		}),
+	)
+
+	var attempts int
+	mux.HandleFunc("/applications", func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.Header().Set("X-Request-Id", "req-"+strconv.Itoa(attempts))
+		w.Header().Set("X-RateLimit-Limit", "100")
+		w.Header().Set("X-RateLimit-Remaining", "42")
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id":"app-1","environment_variables":{"TOKEN":"response-secret"}}`))
+	})
+
+	_, _, err := client.Applications.Create(context.Background(), &CreateApplicationRequest{
+		Name:            "web",
+		EnvironmentVars: map[string]string{"TOKEN": "request-secret"},
+	})
+	if err != nil {
+		t.Fatalf("Applications.Create returned error: %v", err)
+	}
+
+	output := buf.String()
+	for _, secret := range []string{"secret-key", "request-secret", "response-secret"} {
+		if strings.Contains(output, secret) {
+			t.Errorf("Expected %q to be redacted from logs: %s", secret, output)
+		}
+	}
+
+	var records []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		var record map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("Failed to decode log line %q: %v", line, err)
+		}
+		records = append(records, record)
+	}
+
+	if len(records) != 2 {
+		t.Fatalf("Expected 2 log records, got %d", len(records))
+	}
+
+	first, second := records[0], records[1]
+	if first["level"] != "ERROR" || first["status"] != float64(503) || first["attempt"] != float64(1) {
+		t.Errorf("Unexpected first record: %v", first)
+	}
+	if second["level"] != "INFO" || second["status"] != float64(201) || second["attempt"] != float64(2) {
+		t.Errorf("Unexpected second record: %v", second)
+	}
+	if second["method"] != "POST" || second["path"] != "/applications" || second["request_id"] != "req-2" {
+		t.Errorf("Unexpected second record: %v", second)
+	}
+	if second["rate_remaining"] != float64(42) {
+		t.Errorf("Expected rate_remaining 42, got %v", second["rate_remaining"])
+	}
+	for _, key := range []string{"request_body", "response_body"} {
+		if body, _ := second[key].(string); !strings.Contains(body, `"TOKEN":"[REDACTED]"`) {
+			t.Errorf("Expected %s with redacted TOKEN, got %v", key, second[key])
+		}
+	}
+}
+
+func TestRedactBody(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		body string
+		want string
+	}{
+		{
+			name: "database password",
+			path: "/databases/db-1/credentials",
+			body: `{"id":"db-1","password":"secret"}`,
+			want: `{"id":"db-1","password":"[REDACTED]"}`,
+		},
+		{
+			name: "nested environment variables",
+			path: "/applications",
+			body: `[{"id":"app-1","environment_variables":{"API_KEY":"secret"}}]`,
+			want: `[{"environment_variables":{"API_KEY":"[REDACTED]"},"id":"app-1"}]`,
+		},
+		{
+			name: "environment endpoint",
+			path: "/applications/app-1/env",
+			body: `{"API_KEY":"secret","DEBUG":"true"}`,
+			want: `{"API_KEY":"[REDACTED]","DEBUG":"[REDACTED]"}`,
+		},
+		{
+			name: "not json",
+			path: "/applications",
+			body: `plain text`,
+			want: `[REDACTED]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := redactBody(tt.path, []byte(tt.body)); got != tt.want {
+				t.Errorf("redactBody() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSecret(t *testing.T) {
+	var db Database
+	if err := json.Unmarshal([]byte(`{"id":"db-1","username":"admin","password":"hunter2"}`), &db); err != nil {
+		t.Fatalf("Failed to decode database: %v", err)
+	}
+
+	if db.Password.Reveal() != "hunter2" || db.Username.Reveal() != "admin" {
+		t.Errorf("Expected revealed credentials admin/hunter2, got %s/%s", db.Username.Reveal(), db.Password.Reveal())
+	}
+
+	for _, verb := range []string{"%s", "%v", "%+v", "%#v", "%q"} {
+		if output := fmt.Sprintf(verb, db); strings.Contains(output, "hunter2") || strings.Contains(output, "admin") {
+			t.Errorf("Expected %s output to redact credentials, got %s", verb, output)
+		}
+	}
+	if output := fmt.Sprintf("%#v", db.Password); output != `sevalla.Secret("[REDACTED]")` {
+		t.Errorf("Expected %%#v placeholder, got %s", output)
+	}
+
+	data, err := json.Marshal(db)
+	if err != nil {
+		t.Fatalf("Failed to encode database: %v", err)
+	}
+	if !strings.Contains(string(data), `"password":"[REDACTED]"`) || strings.Contains(string(data), "hunter2") {
+		t.Errorf("Expected JSON to redact password, got %s", data)
+	}
+
+	var buf bytes.Buffer
+	slog.New(slog.NewJSONHandler(&buf, nil)).Info("database", "password", db.Password)
+	if strings.Contains(buf.String(), "hunter2") || !strings.Contains(buf.String(), `"password":"[REDACTED]"`) {
+		t.Errorf("Expected log to redact password, got %s", buf.String())
+	}
+
+	if output := fmt.Sprint(Secret("")); output != "" {
+		t.Errorf("Expected empty secret to format as empty, got %q", output)
+	}
+
+	revealed := RevealSecrets(map[string]Secret{"API_KEY": "secret"})
+	if revealed["API_KEY"] != "secret" {
+		t.Errorf("Expected revealed API_KEY secret, got %q", revealed["API_KEY"])
+	}
+}
+
+func TestRedact(t *testing.T) {
+	createReq := &CreateApplicationRequest{
+		Name:            "web",
+		EnvironmentVars: map[string]string{"API_KEY": "secret"},
+	}
+
+	for _, output := range []string{
+		fmt.Sprint(Redact(createReq)),
+		fmt.Sprintf("%+v", Redact(createReq)),
+		fmt.Sprintf("%#v", Redact([]*CreateApplicationRequest{createReq})),
+	} {
+		if strings.Contains(output, `"secret"`) || !strings.Contains(output, `"API_KEY":"[REDACTED]"`) {
+			t.Errorf("Expected redacted environment variables, got %s", output)
+		}
+		if !strings.Contains(output, `"name":"web"`) {
+			t.Errorf("Expected other fields to be kept, got %s", output)
+		}
+	}
+
+	var buf bytes.Buffer
+	slog.New(slog.NewJSONHandler(&buf, nil)).Info("creating application", "request", Redact(createReq))
+
+	var record struct {
+		Request map[string]interface{} `json:"request"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("Failed to decode log line %q: %v", buf.String(), err)
+	}
+	if record.Request["name"] != "web" {
+		t.Errorf("Expected request logged as an object, got %s", buf.String())
+	}
+	if env, _ := record.Request["environment_variables"].(map[string]interface{}); env["API_KEY"] != redacted {
+		t.Errorf("Expected redacted API_KEY in log, got %s", buf.String())
+	}
+
+	data, err := json.Marshal(map[string]interface{}{"request": Redact(createReq)})
+	if err != nil {
+		t.Fatalf("Failed to encode redacted value: %v", err)
+	}
+	if strings.Contains(string(data), `"secret"`) {
+		t.Errorf("Expected JSON to redact environment variables, got %s", data)
+	}
+}
+
+// recordingTracer is a Tracer that records the spans it starts
+type recordingTracer struct {
+	mu    sync.Mutex
+	spans []*recordingSpan
+}
+
+type recordingSpan struct {
+	name   string
+	parent *recordingSpan
+	attrs  map[string]interface{}
+	errs   []error
+	ended  bool
+}
+
+type recordingSpanKey struct{}
+
+func (t *recordingTracer) Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span) {
+	parent, _ := ctx.Value(recordingSpanKey{}).(*recordingSpan)
+	span := &recordingSpan{name: name, parent: parent, attrs: map[string]interface{}{}}
+	span.SetAttributes(attrs...)
+
+	t.mu.Lock()
+	t.spans = append(t.spans, span)
+	t.mu.Unlock()
+
+	return context.WithValue(ctx, recordingSpanKey{}, span), span
+}
+
+func (t *recordingTracer) Inject(ctx context.Context, header http.Header) {
+	if span, ok := ctx.Value(recordingSpanKey{}).(*recordingSpan); ok {
+		header.Set("Traceparent", span.name)
+	}
+}
+
+func (s *recordingSpan) SetAttributes(attrs ...Attribute) {
+	for _, attr := range attrs {
+		s.attrs[attr.Key] = attr.Value
+	}
+}
+
+func (s *recordingSpan) RecordError(err error) { s.errs = append(s.errs, err) }
+func (s *recordingSpan) End()                  { s.ended = true }
+
+func TestClient_WithTracer(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	tracer := &recordingTracer{}
+	client := NewClient(
+		WithBaseURL(server.URL),
+		WithTracer(tracer),
+		WithRetryPolicy(&RetryPolicy{
+			MaxAttempts:          3,
+			BaseDelay:            time.Millisecond,
+			RetryableStatusCodes: []int{http.StatusServiceUnavailable},
+		}),
+	)
+
+	var attempts int
+	mux.HandleFunc("/applications/app-1/deployments", func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if got := r.Header.Get("Traceparent"); got != "sevalla.Applications.Deploy" {
+			t.Errorf("Expected Traceparent header from the Deploy span, got %q", got)
+		}
+		w.Header().Set("X-Request-Id", "req-123")
+		w.Header().Set("Content-Type", "application/json")
+		if attempts < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(`{"message":"Unavailable"}`))
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(&Deployment{ID: "dep-1"})
+	})
+
+	if _, _, err := client.Applications.Deploy(context.Background(), "app-1"); err != nil {
+		t.Fatalf("Applications.Deploy returned error: %v", err)
+	}
+
+	if len(tracer.spans) != 1 {
+		t.Fatalf("Expected 1 span, got %d", len(tracer.spans))
+	}
+
+	span := tracer.spans[0]
+	if span.name != "sevalla.Applications.Deploy" || !span.ended {
+		t.Errorf("Expected ended span sevalla.Applications.Deploy, got %s (ended %v)", span.name, span.ended)
+	}
+
+	want := map[string]interface{}{
+		"sevalla.application.id":    "app-1",
+		"http.response.status_code": http.StatusCreated,
+		"sevalla.request_id":        "req-123",
+		"sevalla.retry_count":       2,
+	}
+	if !reflect.DeepEqual(span.attrs, want) {
+		t.Errorf("Expected attributes %v, got %v", want, span.attrs)
+	}
+
+	if len(span.errs) != 0 {
+		t.Errorf("Expected no recorded errors, got %v", span.errs)
+	}
+}
+
+func TestClient_WithTracerIteratorsAndLogStreams(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	tracer := &recordingTracer{}
+	client := NewClient(WithBaseURL(server.URL), WithTracer(tracer))
+
+	mux.HandleFunc("/pipelines/pipe-1/runs", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == "1" {
+			w.Header().Set("Link", `<`+server.URL+`/pipelines/pipe-1/runs?page=2>; rel="next"`)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode([]*PipelineRun{{ID: "run-" + r.URL.Query().Get("page")}})
+	})
+	mux.HandleFunc("/deployments/dep-1/logs/stream", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Traceparent"); got != "sevalla.Deployments.TailLogs" {
+			t.Errorf("Expected Traceparent header from the TailLogs span, got %q", got)
+		}
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		_, _ = io.WriteString(w, `{"message":"done"}`+"\n")
+	})
+
+	for _, err := range client.Pipelines.ListRunsIter(context.Background(), "pipe-1", nil) {
+		if err != nil {
+			t.Fatalf("ListRunsIter returned error: %v", err)
+		}
+	}
+
+	if len(tracer.spans) != 3 {
+		t.Fatalf("Expected 3 spans, got %d", len(tracer.spans))
+	}
+	iter := tracer.spans[0]
+	if iter.name != "sevalla.Pipelines.ListRunsIter" || !iter.ended || iter.attrs["sevalla.pipeline.id"] != "pipe-1" {
+		t.Errorf("Unexpected iterator span %s (ended %v, attributes %v)", iter.name, iter.ended, iter.attrs)
+	}
+	for _, page := range tracer.spans[1:] {
+		if page.name != "sevalla.Pipelines.ListRuns" || page.parent != iter {
+			t.Errorf("Expected ListRuns span as child of ListRunsIter, got %s", page.name)
+		}
+	}
+
+	stream, err := client.Deployments.TailLogs(context.Background(), "dep-1", nil)
+	if err != nil {
+		t.Fatalf("TailLogs returned error: %v", err)
+	}
+	for range stream.Lines() {
+	}
+	_ = stream.Close()
+
+	tail := tracer.spans[len(tracer.spans)-1]
+	if tail.name != "sevalla.Deployments.TailLogs" || !tail.ended || tail.attrs["sevalla.deployment.id"] != "dep-1" {
+		t.Errorf("Unexpected TailLogs span %s (ended %v, attributes %v)", tail.name, tail.ended, tail.attrs)
+	}
+	if tail.attrs["http.response.status_code"] != http.StatusOK {
+		t.Errorf("Expected the connect's status on the TailLogs span, got %v", tail.attrs)
+	}
+}
+
+func TestClient_WithTracerRecordsErrors(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	tracer := &recordingTracer{}
+	client := NewClient(WithBaseURL(server.URL), WithTracer(tracer))
+
+	mux.HandleFunc("/deployments/dep-1", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(&Deployment{ID: "dep-1", State: StatusFailed})
+	})
+
+	_, err := client.Deployments.WaitForDeployment(context.Background(), "dep-1", nil)
+	var deploymentErr *DeploymentError
+	if !errors.As(err, &deploymentErr) {
+		t.Fatalf("Expected *DeploymentError, got %v", err)
+	}
+
+	if len(tracer.spans) != 2 {
+		t.Fatalf("Expected 2 spans, got %d", len(tracer.spans))
+	}
+
+	wait, get := tracer.spans[0], tracer.spans[1]
+	if wait.name != "sevalla.Deployments.WaitForDeployment" || len(wait.errs) != 1 {
+		t.Errorf("Expected WaitForDeployment span with 1 error, got %s with %v", wait.name, wait.errs)
+	}
+	if get.name != "sevalla.Deployments.Get" || get.parent != wait {
+		t.Errorf("Expected Get span as child of WaitForDeployment, got %s", get.name)
+	}
+	if get.attrs["sevalla.deployment.id"] != "dep-1" {
+		t.Errorf("Expected deployment ID attribute, got %v", get.attrs)
+	}
+
+	notFound := NewClient(WithBaseURL(server.URL), WithTracer(tracer))
+	mux.HandleFunc("/applications/missing", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"message":"Not found","code":"not_found"}`))
+	})
+
+	if _, _, err := notFound.Applications.Get(context.Background(), "missing"); !IsNotFound(err) {
+		t.Fatalf("Expected not found error, got %v", err)
+	}
+
+	span := tracer.spans[len(tracer.spans)-1]
+	if span.attrs["sevalla.error.code"] != "not_found" || span.attrs["http.response.status_code"] != http.StatusNotFound {
+		t.Errorf("Unexpected attributes %v", span.attrs)
+	}
+	if len(span.errs) != 1 {
+		t.Errorf("Expected 1 recorded error, got %v", span.errs)
+	}
+}
+
+func TestClient_IdempotencyKeyReusedAcrossRetries(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := NewClient(
+		WithBaseURL(server.URL),
+		WithRetryPolicy(&RetryPolicy{
+			MaxAttempts:          3,
+			BaseDelay:            time.Millisecond,
+			RetryableStatusCodes: []int{http.StatusBadGateway},
+		}),
+	)
+
+	var keys []string
+	mux.HandleFunc("/applications/app-1/deployments", func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+		if len(keys) < 2 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Idempotent-Replayed", "true")
+		_ = json.NewEncoder(w).Encode(&Deployment{ID: "dep-1"})
+	})
+
+	_, resp, err := client.Applications.Deploy(context.Background(), "app-1")
+	if err != nil {
+		t.Fatalf("Applications.Deploy returned error: %v", err)
+	}
+
+	if len(keys) != 2 || keys[0] == "" || keys[0] != keys[1] {
+		t.Errorf("Expected the same generated key on both attempts, got %q", keys)
+	}
+
+	if !resp.IdempotentReplayed {
+		t.Error("Expected IdempotentReplayed to be true")
+	}
+
+	ctx := ContextWithIdempotencyKey(context.Background(), "deploy-app-1-v42")
+	if _, _, err := client.Applications.Deploy(ctx, "app-1"); err != nil {
+		t.Fatalf("Applications.Deploy returned error: %v", err)
+	}
+
+	if got := keys[len(keys)-1]; got != "deploy-app-1-v42" {
+		t.Errorf("Expected caller-supplied key, got %q", got)
+	}
+}
+
+func TestClient_IdempotencyKeyReusedWhenReissued(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+
+	var keys []string
+	mux.HandleFunc("/applications", func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+		if len(keys) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(&Application{ID: "app-1"})
+	})
+
+	ctx := ContextWithIdempotencyKey(context.Background(), "create-web-v1")
+	if _, _, err := client.Applications.Create(ctx, &CreateApplicationRequest{Name: "web"}); !IsServerError(err) {
+		t.Fatalf("Expected the first call to fail, got %v", err)
+	}
+	if _, _, err := client.Applications.Create(ctx, &CreateApplicationRequest{Name: "web"}); err != nil {
+		t.Fatalf("Applications.Create returned error: %v", err)
+	}
+
+	if !reflect.DeepEqual(keys, []string{"create-web-v1", "create-web-v1"}) {
+		t.Errorf("Expected the caller-supplied key on both calls, got %q", keys)
+	}
+}
+
+func TestClient_NewRequestIdempotencyKeyOnlyOnPost(t *testing.T) {
+	client := NewClient()
+
+	for _, method := range []string{http.MethodGet, http.MethodPut, http.MethodDelete} {
+		req, err := client.NewRequest(context.Background(), method, "applications", nil)
+		if err != nil {
+			t.Fatalf("NewRequest returned error: %v", err)
+		}
+		if key := req.Header.Get("Idempotency-Key"); key != "" {
+			t.Errorf("Expected no Idempotency-Key on %s, got %q", method, key)
+		}
+	}
+
+	first, _ := client.NewRequest(context.Background(), http.MethodPost, "applications", nil)
+	second, _ := client.NewRequest(context.Background(), http.MethodPost, "applications", nil)
+	if first.Header.Get("Idempotency-Key") == second.Header.Get("Idempotency-Key") {
+		t.Error("Expected a new key for each logical call")
+	}
+}
+
+func TestCheckResponse_IdempotencyError(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodPost, "https://api.sevalla.com/v2/applications", nil)
+	req.Header.Set("Idempotency-Key", "key-1")
+
+	resp := &http.Response{
+		Request:    req,
+		StatusCode: http.StatusConflict,
+		Body:       io.NopCloser(strings.NewReader(`{"message":"In progress","code":"idempotency_key_in_use"}`)),
+	}
+
+	err := CheckResponse(resp)
+	idempotencyErr, ok := err.(*IdempotencyError)
+	if !ok {
+		t.Fatalf("Expected *IdempotencyError, got %T", err)
+	}
+
+	if idempotencyErr.Key != "key-1" || idempotencyErr.Code != "idempotency_key_in_use" {
+		t.Errorf("Unexpected error: %+v", idempotencyErr)
+	}
+
+	if !IsIdempotencyError(err) || !IsConflict(err) {
+		t.Error("Expected IsIdempotencyError and IsConflict to be true")
+	}
+
+	resp = &http.Response{
+		Request:    req,
+		StatusCode: http.StatusConflict,
+		Body:       io.NopCloser(strings.NewReader(`{"message":"Name taken","code":"name_taken"}`)),
+	}
+	if err := CheckResponse(resp); IsIdempotencyError(err) {
+		t.Errorf("Expected other conflicts to stay *ErrorResponse, got %T", err)
+	}
+}
+
+func TestWithRequestOptions(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+
+	var attempts int
+	var headers []http.Header
+	mux.HandleFunc("/pipelines/pipe-1/runs", func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		headers = append(headers, r.Header.Clone())
+		if attempts < 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(&PipelineRun{ID: "run-1"})
+	})
+
+	ctx := WithRequestOptions(context.Background(), RequestHeader("X-Correlation-Id", "corr-1"))
+	ctx = WithRequestOptions(ctx,
+		RequestIdempotencyKey("run-pipe-1"),
+		RequestRetryPolicy(&RetryPolicy{
+			MaxAttempts:          2,
+			BaseDelay:            time.Millisecond,
+			RetryableStatusCodes: []int{http.StatusServiceUnavailable},
+		}),
+	)
+
+	run, _, err := client.Pipelines.Run(ctx, "pipe-1")
+	if err != nil {
+		t.Fatalf("Pipelines.Run returned error: %v", err)
+	}
+	if run.ID != "run-1" || attempts != 2 {
+		t.Errorf("Expected run-1 after 2 attempts, got %s after %d", run.ID, attempts)
+	}
+
+	for _, h := range headers {
+		if got := h.Get("X-Correlation-Id"); got != "corr-1" {
+			t.Errorf("Expected X-Correlation-Id 'corr-1', got %q", got)
+		}
+		if got := h.Get("Idempotency-Key"); got != "run-pipe-1" {
+			t.Errorf("Expected Idempotency-Key 'run-pipe-1', got %q", got)
+		}
+	}
+
+	// Without the options the client makes a single attempt
+	attempts = 0
+	if _, _, err := client.Pipelines.Run(context.Background(), "pipe-1"); err == nil {
+		t.Error("Expected error without retry policy, got nil")
+	}
+	if attempts != 1 {
+		t.Errorf("Expected 1 attempt, got %d", attempts)
+	}
+}
+
+func TestWithRequestOptions_Timeout(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+
+	mux.HandleFunc("/databases/db-1", func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(time.Second):
+		case <-r.Context().Done():
+		}
+	})
+
+	ctx := WithRequestOptions(context.Background(), RequestTimeout(20*time.Millisecond))
+
+	start := time.Now()
+	_, _, err := client.Databases.Get(ctx, "db-1")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Expected the request to time out quickly, took %v", elapsed)
+	}
+}
+
+func TestErrorResponse_IsSentinel(t *testing.T) {
+	tests := []struct {
+		status   int
+		sentinel error
+		helper   func(error) bool
+	}{
+		{http.StatusBadRequest, ErrBadRequest, IsBadRequest},
+		{http.StatusUnauthorized, ErrUnauthorized, IsUnauthorized},
+		{http.StatusForbidden, ErrForbidden, IsForbidden},
+		{http.StatusNotFound, ErrNotFound, IsNotFound},
+		{http.StatusConflict, ErrConflict, IsConflict},
+		{http.StatusUnprocessableEntity, ErrUnprocessableEntity, IsUnprocessableEntity},
+		{http.StatusTooManyRequests, ErrRateLimited, IsRateLimited},
+		{http.StatusBadGateway, ErrServerError, IsServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			err := CheckResponse(&http.Response{
+				StatusCode: tt.status,
+				Header:     http.Header{},
+				Body:       io.NopCloser(strings.NewReader(`{"message":"failed"}`)),
+			})
+			wrapped := fmt.Errorf("deploying web: %w", err)
+
+			if !errors.Is(wrapped, tt.sentinel) {
+				t.Errorf("Expected errors.Is(%v, %v)", wrapped, tt.sentinel)
+			}
+			if !tt.helper(wrapped) {
+				t.Errorf("Expected helper to match the wrapped error")
+			}
+			if errors.Is(wrapped, ErrNotFound) != (tt.status == http.StatusNotFound) {
+				t.Errorf("Unexpected ErrNotFound match for status %d", tt.status)
+			}
+
+			var errResp *ErrorResponse
+			if !errors.As(wrapped, &errResp) || errResp.Message != "failed" {
+				t.Errorf("Expected errors.As to find the *ErrorResponse, got %v", errResp)
+			}
+		})
+	}
+}
+
+func TestIsTemporary(t *testing.T) {
+	newErr := func(status int) error {
+		return CheckResponse(&http.Response{StatusCode: status, Header: http.Header{}, Body: http.NoBody})
+	}
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"rate limited", newErr(http.StatusTooManyRequests), true},
+		{"service unavailable", fmt.Errorf("wrapped: %w", newErr(http.StatusServiceUnavailable)), true},
+		{"internal server error", newErr(http.StatusInternalServerError), false},
+		{"not found", newErr(http.StatusNotFound), false},
+		{"network timeout", &url.Error{Op: "Get", URL: "https://api.sevalla.com", Err: timeoutError{}}, true},
+		{"deadline exceeded", context.DeadlineExceeded, false},
+		{"other", errors.New("boom"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsTemporary(tt.err); got != tt.want {
+				t.Errorf("IsTemporary() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// timeoutError is a network error reporting a timeout
+type timeoutError struct{}
+
+func (timeoutError) Error() string { return "i/o timeout" }
+func (timeoutError) Timeout() bool { return true }
+
+func TestClient_DoDecodeError(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+
+	body := `{"id": "app-1", "name": ` + strings.Repeat("x", 300)
+	mux.HandleFunc("/applications/app-1", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	})
+
+	_, _, err := client.Applications.Get(context.Background(), "app-1")
+
+	var decodeErr *DecodeError
+	if !errors.As(err, &decodeErr) {
+		t.Fatalf("Expected *DecodeError, got %T: %v", err, err)
+	}
+
+	if decodeErr.Method != http.MethodGet || !strings.HasSuffix(decodeErr.URL, "/applications/app-1") || decodeErr.StatusCode != http.StatusOK {
+		t.Errorf("Unexpected decode error: %+v", decodeErr)
+	}
+
+	if !strings.HasPrefix(decodeErr.Body, `{"id": "app-1"`) || len(decodeErr.Body) != 256+len("...") {
+		t.Errorf("Expected truncated body snippet, got %q", decodeErr.Body)
+	}
+
+	var syntaxErr *json.SyntaxError
+	if !errors.As(err, &syntaxErr) {
+		t.Errorf("Expected the underlying *json.SyntaxError, got %v", decodeErr.Err)
+	}
+}
+
+func TestClient_DoRetriesTemporaryErrorsByDefault(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := NewClient(
+		WithBaseURL(server.URL),
+		WithRetryPolicy(&RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}),
+	)
+
+	var attempts int
+	mux.HandleFunc("/deployments/dep-1", func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		switch attempts {
+		case 1:
+			w.WriteHeader(http.StatusGatewayTimeout)
+		case 2:
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			t.Error("Expected no retry after a 500 response")
+		}
+	})
+
+	_, _, err := client.Deployments.Get(context.Background(), "dep-1")
+	if !IsServerError(err) || attempts != 2 {
+		t.Errorf("Expected a server error after 2 attempts, got %v after %d", err, attempts)
+	}
+}
+
+func TestClient_ValidationErrors(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+
+	mux.HandleFunc("/applications", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_, _ = w.Write([]byte(`{
+			"message": "validation failed",
+			"code": "validation_failed",
+			"errors": [
+				{"field": "repository_url", "code": "invalid", "message": "must be a URL"},
+				{"field": "environment_variables.API_KEY", "code": "too_long", "message": "is too long"},
+				{"field": "repository_url", "code": "unreachable", "message": "cannot be cloned"},
+				{"field": "unknown_field", "code": "invalid", "message": "is invalid"}
+			]
+		}`))
+	})
+
+	_, _, err := client.Applications.Create(context.Background(), &CreateApplicationRequest{Name: "web"})
+	err = fmt.Errorf("creating app: %w", err)
+
+	var verr *ValidationErrors
+	if !errors.As(err, &verr) {
+		t.Fatalf("Expected *ValidationErrors, got %T: %v", err, err)
+	}
+
+	want := []string{"RepositoryURL", "EnvironmentVars.API_KEY", "RepositoryURL", "unknown_field"}
+	var got []string
+	for _, fe := range verr.Fields {
+		got = append(got, fe.Field)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected fields %v, got %v", want, got)
+	}
+
+	if errs := verr.Field("RepositoryURL"); len(errs) != 2 || errs[1].Code != "unreachable" || errs[0].APIField != "repository_url" {
+		t.Errorf("Unexpected RepositoryURL errors: %+v", errs)
+	}
+
+	if len(verr.ByField()) != 3 {
+		t.Errorf("Expected 3 fields, got %v", verr.ByField())
+	}
+
+	var fieldErr *ValidationError
+	if !errors.As(err, &fieldErr) || fieldErr.Field != "RepositoryURL" {
+		t.Errorf("Expected errors.As to find the first *ValidationError, got %v", fieldErr)
+	}
+
+	if !IsUnprocessableEntity(err) || verr.ErrorResponse.Code != "validation_failed" {
+		t.Errorf("Expected a 422 validation_failed error, got %v", err)
+	}
+
+	if !strings.Contains(err.Error(), "RepositoryURL: must be a URL") {
+		t.Errorf("Expected error message to name the Go field, got %s", err.Error())
+	}
+}
+
+func TestGoFieldPath(t *testing.T) {
+	tests := []struct {
+		body interface{}
+		path string
+		want string
+	}{
+		{&CreateDatabaseRequest{}, "storage_gb", "Storage"},
+		{&UpdatePipelineRequest{}, "name", "Name"},
+		{&CreatePipelineRequest{}, "environment.TOKEN", "Environment.TOKEN"},
+		{map[string]string{}, "API_KEY", "API_KEY"},
+		{nil, "name", "name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := goFieldPath(reflect.TypeOf(tt.body), tt.path); got != tt.want {
+				t.Errorf("goFieldPath() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequestValidate(t *testing.T) {
+	port := 70000
+	empty := ""
+	plan := Plan("huge")
+
+	tests := []struct {
+		name string
+		req  Validator
+		want []string
+	}{
+		{"valid application", &CreateApplicationRequest{Name: "web", RepositoryURL: "https://github.com/acme/web", Region: RegionUSEast, Port: 8080}, nil},
+		{"scp repository", &CreateApplicationRequest{Name: "web", RepositoryURL: "git@github.com:acme/web.git"}, nil},
+		{"invalid application", &CreateApplicationRequest{RepositoryURL: "not a url", Region: "mars", Replicas: -1, Port: port}, []string{"Name", "RepositoryURL", "Region", "Replicas", "Port"}},
+		{"invalid update", &UpdateApplicationRequest{Name: &empty, Plan: &plan, Port: &port}, []string{"Name", "Plan", "Port"}},
+		{"valid update", &UpdateApplicationRequest{}, nil},
+		{"invalid scale", &ScaleApplicationRequest{Replicas: -2}, []string{"Replicas"}},
+		{"invalid database", &CreateDatabaseRequest{Name: "db", Type: "oracle", Storage: -1}, []string{"Type", "Storage"}},
+		{"missing engine", &CreateDatabaseRequest{Name: "db"}, []string{"Type"}},
+		{"valid database", &CreateDatabaseRequest{Name: "db", Type: EnginePostgreSQL, Version: "16", Storage: 10}, nil},
+		{"default version and storage", &CreateDatabaseRequest{Name: "db", Type: EngineRedis}, nil},
+		{"unknown version", &CreateDatabaseRequest{Name: "db", Type: EngineMySQL, Version: "16"}, []string{"Version"}},
+		{"version of another engine", &CreateDatabaseRequest{Name: "db", Type: EngineRedis, Version: "8.0"}, []string{"Version"}},
+		{"invalid database update", &UpdateDatabaseRequest{Storage: new(int)}, []string{"Storage"}},
+		{"invalid static site", &CreateStaticSiteRequest{Name: "docs"}, []string{"RepositoryURL"}},
+		{"empty pipeline", &CreatePipelineRequest{Name: "ci"}, []string{"Steps"}},
+		{
+			"invalid steps",
+			&UpdatePipelineRequest{Steps: []PipelineStep{
+				{Name: "build", Command: "make"},
+				{Name: "build", DependsOn: []string{"lint"}},
+			}},
+			[]string{"Steps.1.Name", "Steps.1.Command", "Steps.1.DependsOn"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.req.Validate()
+			if tt.want == nil {
+				if err != nil {
+					t.Fatalf("Expected no error, got %v", err)
+				}
+				return
+			}
+
+			var verr *ValidationErrors
+			if !errors.As(err, &verr) {
+				t.Fatalf("Expected *ValidationErrors, got %T: %v", err, err)
+			}
+
+			var got []string
+			for _, fe := range verr.Fields {
+				got = append(got, fe.Field)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Expected fields %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestClient_WithValidation(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id": "app-123"}`))
+	}))
+	defer server.Close()
+
+	req := &CreateApplicationRequest{Name: "web"}
+
+	client := NewClient(WithBaseURL(server.URL), WithValidation(true))
+	_, _, err := client.Applications.Create(context.Background(), req)
+
+	var verr *ValidationErrors
+	if !errors.As(err, &verr) {
+		t.Fatalf("Expected *ValidationErrors, got %T: %v", err, err)
+	}
+	if calls != 0 {
+		t.Errorf("Expected no request to be sent, got %d", calls)
+	}
+	if !errors.Is(err, ErrValidation) || IsUnprocessableEntity(err) || IsTemporary(err) {
+		t.Errorf("Expected a client-side validation error, got %v", err)
+	}
+	if fe := verr.Field("RepositoryURL"); len(fe) != 1 || fe[0].APIField != "repository_url" || fe[0].Code != "required" {
+		t.Errorf("Unexpected RepositoryURL errors: %+v", fe)
+	}
+	if err.Error() != "sevalla: validation failed - RepositoryURL: is required" {
+		t.Errorf("Unexpected error message: %s", err.Error())
+	}
+	if verr.ErrorResponse != nil {
+		t.Errorf("Expected no ErrorResponse for a client-side error, got %+v", verr.ErrorResponse)
+	}
+
+	_, _, err = client.Applications.Create(context.Background(), nil)
+	if !errors.Is(err, ErrValidation) || err.Error() != "sevalla: validation failed - request is required" {
+		t.Errorf("Expected a validation error for a nil request, got %v", err)
+	}
+
+	client = NewClient(WithBaseURL(server.URL))
+	if _, _, err := client.Applications.Create(context.Background(), req); err != nil {
+		t.Fatalf("Expected validation to be disabled by default, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("Expected 1 request, got %d", calls)
+	}
+}
+
+func TestMemoryCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	cache := NewMemoryCache(2)
+	cache.Set("a", &CacheEntry{ETag: `"a"`})
+	cache.Set("b", &CacheEntry{ETag: `"b"`})
+
+	if _, ok := cache.Get("a"); !ok {
+		t.Fatal("Expected entry a to be cached")
+	}
+
+	cache.Set("c", &CacheEntry{ETag: `"c"`})
+
+	if _, ok := cache.Get("b"); ok {
+		t.Error("Expected least recently used entry b to be evicted")
+	}
+	if _, ok := cache.Get("a"); !ok {
+		t.Error("Expected entry a to be kept")
+	}
+	if cache.Len() != 2 {
+		t.Errorf("Expected 2 entries, got %d", cache.Len())
+	}
+
+	cache.Delete("a")
+	if _, ok := cache.Get("a"); ok {
+		t.Error("Expected entry a to be deleted")
+	}
+}
+
+func TestClient_WithCache(t *testing.T) {
+	var gets, notModified int
+	var gone bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gets++
+		if gone {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message": "not found"}`))
+			return
+		}
+
+		w.Header().Set("X-Request-Id", "req-"+strconv.Itoa(gets))
+		w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(100-gets))
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		w.Header().Set("X-RateLimit-Limit", "100")
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Link", `<https://api.sevalla.com/v2/applications?page=2>; rel="next"`)
+		_, _ = w.Write([]byte(`[{"id": "app-1", "name": "web"}]`))
+	}))
+	defer server.Close()
+
+	cache := NewMemoryCache(10)
+	client := NewClient(WithBaseURL(server.URL), WithCache(cache))
+	ctx := context.Background()
+
+	apps, resp, err := client.Applications.List(ctx, nil)
+	if err != nil {
+		t.Fatalf("List returned error: %v", err)
+	}
+	if resp.CacheHit || len(apps) != 1 || cache.Len() != 1 {
+		t.Fatalf("Expected a stored miss, got hit=%v apps=%d entries=%d", resp.CacheHit, len(apps), cache.Len())
+	}
+
+	apps, resp, err = client.Applications.List(ctx, nil)
+	if err != nil {
+		t.Fatalf("List returned error: %v", err)
+	}
+	if !resp.CacheHit || resp.StatusCode != http.StatusNotModified || notModified != 1 {
+		t.Errorf("Expected a 304 cache hit, got hit=%v status=%d", resp.CacheHit, resp.StatusCode)
+	}
+	if len(apps) != 1 || apps[0].Name != "web" {
+		t.Errorf("Expected cached applications, got %+v", apps)
+	}
+	if resp.NextPage != 2 || resp.Rate.Remaining != 98 {
+		t.Errorf("Expected cached Link and fresh rate headers, got next=%d remaining=%d", resp.NextPage, resp.Rate.Remaining)
+	}
+	if resp.Rate.Limit != 0 || resp.Header.Get("X-Request-Id") != "req-2" {
+		t.Errorf("Expected rate limit and request ID of the 304 only, got limit=%d id=%s", resp.Rate.Limit, resp.Header.Get("X-Request-Id"))
+	}
+	if resp.Header.Get("Content-Type") != "application/json" || resp.ETag != `"v1"` {
+		t.Errorf("Expected cached body headers, got %v", resp.Header)
+	}
+
+	gone = true
+	if _, _, err := client.Applications.List(ctx, nil); !IsNotFound(err) {
+		t.Errorf("Expected not found error, got %v", err)
+	}
+	if cache.Len() != 0 {
+		t.Errorf("Expected 404 to drop the cached entry, got %d entries", cache.Len())
+	}
+
+	uncached := NewClient(WithBaseURL(server.URL))
+	gone = false
+	_, _, _ = uncached.Applications.List(ctx, nil)
+	if _, resp, _ := uncached.Applications.List(ctx, nil); resp.CacheHit {
+		t.Error("Expected caching to be disabled by default")
+	}
+}
+
+func TestClient_PreconditionFailed(t *testing.T) {
+	var gotIfMatch, gotIfUnmodifiedSince string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotIfMatch = r.Header.Get("If-Match")
+		gotIfUnmodifiedSince = r.Header.Get("If-Unmodified-Since")
+		w.WriteHeader(http.StatusPreconditionFailed)
+		_, _ = w.Write([]byte(`{"message": "resource was modified", "code": "precondition_failed"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	updatedAt := time.Date(2025, 10, 1, 12, 0, 0, 0, time.UTC)
+	ctx := WithRequestOptions(context.Background(), RequestIfMatch(`"v1"`), RequestIfUnmodifiedSince(updatedAt))
+
+	_, _, err := client.Applications.Update(ctx, "app-1", &UpdateApplicationRequest{Name: String("api")})
+
+	var pfErr *PreconditionFailedError
+	if !errors.As(err, &pfErr) {
+		t.Fatalf("Expected *PreconditionFailedError, got %T: %v", err, err)
+	}
+	if gotIfMatch != `"v1"` || gotIfUnmodifiedSince != "Wed, 01 Oct 2025 12:00:00 GMT" {
+		t.Errorf("Unexpected preconditions sent: If-Match=%s If-Unmodified-Since=%s", gotIfMatch, gotIfUnmodifiedSince)
+	}
+	if pfErr.IfMatch != `"v1"` {
+		t.Errorf("Expected error to carry If-Match \"v1\", got %s", pfErr.IfMatch)
+	}
+	if !IsPreconditionFailed(err) || !IsConflict(err) || IsTemporary(err) {
+		t.Errorf("Expected a non-temporary conflict, got %v", err)
+	}
+}
+
+func TestApplicationsService_Modify(t *testing.T) {
+	var gets, patches int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		version := fmt.Sprintf(`"v%d"`, gets)
+		switch r.Method {
+		case http.MethodGet:
+			gets++
+			w.Header().Set("ETag", fmt.Sprintf(`"v%d"`, gets))
+			_, _ = fmt.Fprintf(w, `{"id": "app-1", "replicas": %d}`, gets)
+		case http.MethodPatch:
+			patches++
+			// Another writer changes the application after the first read
+			if patches == 1 || r.Header.Get("If-Match") != version {
+				w.WriteHeader(http.StatusPreconditionFailed)
+				_, _ = w.Write([]byte(`{"message": "resource was modified"}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"id": "app-1", "replicas": 3}`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+
+	var seen []int
+	app, _, err := client.Applications.Modify(context.Background(), "app-1", func(app *Application) (*UpdateApplicationRequest, error) {
+		seen = append(seen, app.Replicas)
+		return &UpdateApplicationRequest{Replicas: Int(app.Replicas + 1)}, nil
+	})
+	if err != nil {
+		t.Fatalf("Modify returned error: %v", err)
+	}
+	if app.Replicas != 3 || !reflect.DeepEqual(seen, []int{1, 2}) || patches != 2 {
+		t.Errorf("Expected mutate to rerun on the fresh copy, got replicas=%d seen=%v patches=%d", app.Replicas, seen, patches)
+	}
+
+	patches = 0
+	app, _, err = client.Applications.Modify(context.Background(), "app-1", func(app *Application) (*UpdateApplicationRequest, error) {
+		return nil, nil
+	})
+	if err != nil || app.ID != "app-1" || patches != 0 {
+		t.Errorf("Expected a nil update to send nothing, got app=%v patches=%d err=%v", app, patches, err)
+	}
+
+	mutateErr := errors.New("refused")
+	if _, _, err := client.Applications.Modify(context.Background(), "app-1", func(*Application) (*UpdateApplicationRequest, error) {
+		return nil, mutateErr
+	}); !errors.Is(err, mutateErr) {
+		t.Errorf("Expected the mutate error, got %v", err)
+	}
+}
+
+func TestModify_GivesUp(t *testing.T) {
+	var patches int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPatch {
+			patches++
+			w.WriteHeader(http.StatusPreconditionFailed)
+			_, _ = w.Write([]byte(`{"message": "resource was modified"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"id": "db-1", "updated_at": "2025-10-01T12:00:00Z"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	_, _, err := client.Databases.Modify(context.Background(), "db-1", func(*Database) (*UpdateDatabaseRequest, error) {
+		return &UpdateDatabaseRequest{Name: String("db")}, nil
+	})
+
+	var pfErr *PreconditionFailedError
+	if !errors.As(err, &pfErr) || patches != maxModifyAttempts {
+		t.Fatalf("Expected precondition failure after %d attempts, got %d: %v", maxModifyAttempts, patches, err)
+	}
+	if pfErr.IfUnmodifiedSince != "Wed, 01 Oct 2025 12:00:00 GMT" {
+		t.Errorf("Expected UpdatedAt as precondition without an ETag, got %q", pfErr.IfUnmodifiedSince)
+	}
+}
+
+// isolateConfig clears the environment read by LoadConfig and points the
+// default profile file into an empty directory
+func isolateConfig(t *testing.T) {
+	t.Helper()
+	for _, env := range []string{EnvAPIKey, EnvBaseURL, EnvTimeout, EnvCompanyID, EnvProfile, EnvConfigFile} {
+		t.Setenv(env, "")
+	}
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+}
+
+func writeConfigFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	return path
+}
+
+const testConfigFile = `
+# Shared by all profiles
+default_profile = "staging"
+timeout = 45
+
+[staging]
+api_key = "staging-key" # trailing comment
+base_url = "https://staging.example.com/v2"
+
+[production]
+api_key = 'prod-key'
+company_id = "company-#1"
+timeout = "1m"
+`
+
+func TestLoadConfig_Profiles(t *testing.T) {
+	isolateConfig(t)
+	path := writeConfigFile(t, testConfigFile)
+
+	cfg, err := LoadConfig(ConfigFile(path))
+	if err != nil {
+		t.Fatalf("LoadConfig returned error: %v", err)
+	}
+	want := &Config{Profile: "staging", File: path, APIKey: "staging-key", BaseURL: "https://staging.example.com/v2", Timeout: 45 * time.Second}
+	if !reflect.DeepEqual(cfg, want) {
+		t.Errorf("Expected %+v, got %+v", want, cfg)
+	}
+
+	t.Setenv(EnvProfile, "production")
+	cfg, err = LoadConfig(ConfigFile(path))
+	if err != nil {
+		t.Fatalf("LoadConfig returned error: %v", err)
+	}
+	if cfg.APIKey != "prod-key" || cfg.CompanyID != "company-#1" || cfg.Timeout != time.Minute || cfg.BaseURL != "" {
+		t.Errorf("Unexpected production config: %+v", cfg)
+	}
+
+	cfg, err = LoadConfig(ConfigFile(path), ConfigProfile("staging"))
+	if err != nil || cfg.Profile != "staging" {
+		t.Errorf("Expected ConfigProfile to take precedence over %s, got %+v (err %v)", EnvProfile, cfg, err)
+	}
+
+	t.Setenv(EnvConfigFile, path)
+	t.Setenv(EnvAPIKey, "env-key")
+	t.Setenv(EnvTimeout, "5s")
+	cfg, err = LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig returned error: %v", err)
+	}
+	if cfg.APIKey != "env-key" || cfg.Timeout != 5*time.Second || cfg.CompanyID != "company-#1" {
+		t.Errorf("Expected environment variables to override the profile, got %+v", cfg)
+	}
+}
+
+func TestLoadConfig_ExplicitProfileOverridesEnvironment(t *testing.T) {
+	isolateConfig(t)
+	path := writeConfigFile(t, testConfigFile)
+
+	t.Setenv(EnvAPIKey, "env-key")
+	t.Setenv(EnvBaseURL, "https://env.example.com/v2")
+	t.Setenv(EnvTimeout, "5s")
+
+	cfg, err := LoadConfig(ConfigFile(path), ConfigProfile("staging"))
+	if err != nil {
+		t.Fatalf("LoadConfig returned error: %v", err)
+	}
+	if cfg.APIKey != "staging-key" || cfg.BaseURL != "https://staging.example.com/v2" {
+		t.Errorf("Expected the selected profile's key and base URL, got %+v", cfg)
+	}
+	if cfg.Timeout != 5*time.Second {
+		t.Errorf("Expected %s to fill settings missing from the profile, got %s", EnvTimeout, cfg.Timeout)
+	}
+
+	t.Setenv(EnvProfile, "staging")
+	cfg, err = LoadConfig(ConfigFile(path))
+	if err != nil {
+		t.Fatalf("LoadConfig returned error: %v", err)
+	}
+	if cfg.APIKey != "env-key" || cfg.BaseURL != "https://env.example.com/v2" {
+		t.Errorf("Expected environment variables to override a profile from %s, got %+v", EnvProfile, cfg)
+	}
+}
+
+func TestLoadConfig_Errors(t *testing.T) {
+	isolateConfig(t)
+	path := writeConfigFile(t, testConfigFile)
+
+	if _, err := LoadConfig(); !errors.Is(err, ErrMissingAPIKey) {
+		t.Errorf("Expected ErrMissingAPIKey without configuration, got %v", err)
+	}
+
+	if _, err := LoadConfig(ConfigFile(path), ConfigProfile("qa")); err == nil || !strings.Contains(err.Error(), `profile "qa" not found`) {
+		t.Errorf("Expected unknown profile error, got %v", err)
+	}
+
+	if _, err := LoadConfig(ConfigFile(filepath.Join(t.TempDir(), "missing.toml"))); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected a missing explicit file to be an error, got %v", err)
+	}
+
+	t.Setenv(EnvAPIKey, "env-key")
+	t.Setenv(EnvTimeout, "soon")
+	if _, err := LoadConfig(); err == nil || !strings.Contains(err.Error(), "invalid timeout") {
+		t.Errorf("Expected invalid timeout error, got %v", err)
+	}
+
+	for _, content := range []string{
+		"api_key = unquoted",
+		"[staging\napi_key = \"x\"",
+		"[staging]\napi_token = \"x\"",
+		"[a]\n[a]",
+		"api_key",
+	} {
+		if _, err := LoadConfig(ConfigFile(writeConfigFile(t, content))); err == nil || !strings.Contains(err.Error(), "line") {
+			t.Errorf("Expected a parse error with a line number for %q, got %v", content, err)
+		}
+	}
+}
+
+func TestNewClientFromEnv(t *testing.T) {
+	isolateConfig(t)
+
+	var gotAuth, gotCompany string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		gotCompany = r.URL.Query().Get("company_id")
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	t.Setenv(EnvAPIKey, "env-key")
+	t.Setenv(EnvBaseURL, server.URL)
+	t.Setenv(EnvCompanyID, "company-1")
+	t.Setenv(EnvTimeout, "7")
+
+	client, err := NewClientFromEnv()
+	if err != nil {
+		t.Fatalf("NewClientFromEnv returned error: %v", err)
+	}
+	if client.client.Timeout != 7*time.Second {
+		t.Errorf("Expected 7s timeout, got %s", client.client.Timeout)
+	}
+
+	if _, _, err := client.StaticSites.List(context.Background(), nil); err != nil {
+		t.Fatalf("List returned error: %v", err)
+	}
+	if gotAuth != "Bearer env-key" || gotCompany != "company-1" {
+		t.Errorf("Expected configured key and company, got %q and %q", gotAuth, gotCompany)
+	}
+
+	if _, _, err := client.StaticSites.List(context.Background(), &ListOptions{CompanyID: "company-2"}); err != nil {
+		t.Fatalf("List returned error: %v", err)
+	}
+	if gotCompany != "company-2" {
+		t.Errorf("Expected ListOptions.CompanyID to win, got %q", gotCompany)
+	}
+
+	t.Setenv(EnvAPIKey, "")
+	if _, err := NewClientFromEnv(); !errors.Is(err, ErrMissingAPIKey) {
+		t.Errorf("Expected ErrMissingAPIKey, got %v", err)
+	}
+}
+
+func TestClient_CompanyIDOnlyOnCompanyLists(t *testing.T) {
+	client := NewClient(WithCompanyID("company-1"))
+
+	for _, path := range []string{"applications", "databases", "deployments", "pipelines", "static-sites"} {
+		req, err := client.NewRequestWithQuery(context.Background(), http.MethodGet, path, &ListOptions{Page: 2})
+		if err != nil {
+			t.Fatalf("NewRequestWithQuery returned error: %v", err)
+		}
+		if got := req.URL.Query().Get("company_id"); got != "company-1" {
+			t.Errorf("Expected company_id on %s, got %q", path, got)
+		}
+	}
+
+	for _, path := range []string{
+		"applications/app-1/deployments",
+		"applications/app-1/logs/stream",
+		"databases/db-1/backups",
+		"pipelines/pipe-1/runs",
+	} {
+		req, err := client.NewRequestWithQuery(context.Background(), http.MethodGet, path, &ListOptions{Page: 2})
+		if err != nil {
+			t.Fatalf("NewRequestWithQuery returned error: %v", err)
+		}
+		if req.URL.Query().Has("company_id") {
+			t.Errorf("Expected no company_id on %s, got %s", path, req.URL.RawQuery)
+		}
+	}
+}
+
+// rotatingCredentials hands out key-1, key-2, ... advancing on Invalidate
+type rotatingCredentials struct {
+	mu          sync.Mutex
+	version     int
+	invalidated int
+}
+
+func (r *rotatingCredentials) APIKey(context.Context) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return fmt.Sprintf("key-%d", r.version+1), nil
+}
+
+func (r *rotatingCredentials) Invalidate() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.version++
+	r.invalidated++
+}
+
+func TestClient_CredentialsRefreshOn401(t *testing.T) {
+	var valid atomic.Value
+	valid.Store("key-2")
+
+	var auths []string
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		auths = append(auths, r.Header.Get("Authorization"))
+		mu.Unlock()
+
+		if r.Header.Get("Authorization") != "Bearer "+valid.Load().(string) {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"message": "invalid API key"}`))
+			return
+		}
+		if !bytes.Contains(body, []byte(`"web"`)) {
+			t.Errorf("Expected the request body to be resent, got %s", body)
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id": "app-1"}`))
+	}))
+	defer server.Close()
+
+	creds := &rotatingCredentials{}
+	client := NewClient(WithBaseURL(server.URL), WithCredentials(creds))
+
+	if _, _, err := client.Applications.Create(context.Background(), &CreateApplicationRequest{Name: "web"}); err != nil {
+		t.Fatalf("Create returned error: %v", err)
+	}
+	if want := []string{"Bearer key-1", "Bearer key-2"}; !reflect.DeepEqual(auths, want) {
+		t.Errorf("Expected %v, got %v", want, auths)
+	}
+
+	// A revoked key is refreshed once, then the 401 is returned
+	valid.Store("key-9")
+	auths = nil
+	if _, _, err := client.Applications.Get(context.Background(), "app-1"); !IsUnauthorized(err) {
+		t.Errorf("Expected unauthorized error, got %v", err)
+	}
+	if len(auths) != 2 || creds.invalidated != 2 {
+		t.Errorf("Expected a single refresh, got requests %v and %d invalidations", auths, creds.invalidated)
+	}
+
+	// An unchanged key is not retried
+	client = NewClient(WithBaseURL(server.URL), WithAPIKey("static"))
+	auths = nil
+	if _, _, err := client.Applications.Get(context.Background(), "app-1"); !IsUnauthorized(err) || len(auths) != 1 {
+		t.Errorf("Expected one unauthorized request, got %v (%v)", auths, err)
+	}
+}
+
+func TestCredentialsProviders(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("env", func(t *testing.T) {
+		t.Setenv("SEVALLA_TEST_KEY", "env-key")
+		creds := EnvCredentials("SEVALLA_TEST_KEY")
+		if key, err := creds.APIKey(ctx); err != nil || key != "env-key" {
+			t.Errorf("Expected env-key, got %q (err %v)", key, err)
+		}
+
+		t.Setenv("SEVALLA_TEST_KEY", "")
+		if _, err := creds.APIKey(ctx); !errors.Is(err, ErrMissingAPIKey) {
+			t.Errorf("Expected ErrMissingAPIKey, got %v", err)
+		}
+	})
+
+	t.Run("file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "api-key")
+		if err := os.WriteFile(path, []byte("file-key-1\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		creds := NewFileCredentials(path)
+		if key, err := creds.APIKey(ctx); err != nil || key != "file-key-1" {
+			t.Fatalf("Expected file-key-1, got %q (err %v)", key, err)
+		}
+
+		if err := os.WriteFile(path, []byte("file-key-22\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if key, _ := creds.APIKey(ctx); key != "file-key-22" {
+			t.Errorf("Expected the rotated key, got %q", key)
+		}
+
+		if err := os.Remove(path); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := creds.APIKey(ctx); !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("Expected a missing file error, got %v", err)
+		}
+	})
+
+	t.Run("command", func(t *testing.T) {
+		if _, err := exec.LookPath("sh"); err != nil {
+			t.Skip("sh not available")
+		}
+
+		counter := filepath.Join(t.TempDir(), "count")
+		creds := NewCommandCredentials(0, "sh", "-c", `echo x >> "$0"; echo "cmd-key-$(wc -l < "$0" | tr -d ' ')"`, counter)
+
+		for i := 0; i < 2; i++ {
+			if key, err := creds.APIKey(ctx); err != nil || key != "cmd-key-1" {
+				t.Fatalf("Expected cached cmd-key-1, got %q (err %v)", key, err)
+			}
+		}
+
+		creds.Invalidate()
+		if key, _ := creds.APIKey(ctx); key != "cmd-key-2" {
+			t.Errorf("Expected the command to run again after Invalidate, got %q", key)
+		}
+
+		failing := NewCommandCredentials(0, "sh", "-c", "echo denied >&2; exit 1")
+		if _, err := failing.APIKey(ctx); err == nil || !strings.Contains(err.Error(), "denied") {
+			t.Errorf("Expected the command's stderr in the error, got %v", err)
+		}
+	})
+}
+
+func TestClient_CredentialsConcurrent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"id": "app-1"}`))
+	}))
+	defer server.Close()
+
+	creds := &rotatingCredentials{}
+	client := NewClient(WithBaseURL(server.URL), WithCredentials(creds))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			creds.Invalidate()
+			if _, _, err := client.Applications.Get(context.Background(), "app-1"); err != nil {
+				t.Errorf("Get returned error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestClient_WithDryRun(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	mux.HandleFunc("/applications/app-1", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			t.Errorf("Expected only GET requests to be sent, got %s", r.Method)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"app-1","name":"web"}`))
+	})
+
+	var plan DryRunPlan
+	client := NewClient(
+		WithAPIKey("test-key"),
+		WithBaseURL(server.URL),
+		WithDryRun(&plan),
+	)
+	ctx := context.Background()
+
+	app, _, err := client.Applications.Get(ctx, "app-1")
+	if err != nil {
+		t.Fatalf("Applications.Get returned error: %v", err)
+	}
+	if app.Name != "web" {
+		t.Errorf("Expected GET to reach the server, got %+v", app)
+	}
+
+	_, resp, err := client.Applications.Update(ctx, "app-1", &UpdateApplicationRequest{
+		EnvironmentVars: map[string]string{"API_KEY": "secret"},
+	})
+	if err != nil {
+		t.Fatalf("Applications.Update returned error: %v", err)
+	}
+	if !resp.DryRun || resp.StatusCode != http.StatusAccepted {
+		t.Errorf("Expected synthetic 202 response, got %d (dry run %v)", resp.StatusCode, resp.DryRun)
+	}
+
+	if _, err := client.Applications.Delete(ctx, "app-1"); err != nil {
+		t.Fatalf("Applications.Delete returned error: %v", err)
+	}
+
+	requests := plan.Requests()
+	if len(requests) != 2 {
+		t.Fatalf("Expected 2 planned requests, got %d", len(requests))
+	}
+	if requests[0].Method != "PATCH" || requests[0].Path != "/applications/app-1" {
+		t.Errorf("Unexpected first planned request: %+v", requests[0])
+	}
+	if string(requests[0].Body) != `{"environment_variables":{"API_KEY":"secret"}}` {
+		t.Errorf("Expected request body to be recorded, got %s", requests[0].Body)
+	}
+	if requests[1].Method != "DELETE" || requests[1].Body != nil {
+		t.Errorf("Unexpected second planned request: %+v", requests[1])
+	}
+
+	want := `~ PATCH /applications/app-1
+~     {
+~       "environment_variables": {
+~         "API_KEY": "[REDACTED]"
+~       }
+~     }
+- DELETE /applications/app-1
+
+2 requests planned, none sent.
+`
+	if got := plan.String(); got != want {
+		t.Errorf("Unexpected plan:\n%s\nwant:\n%s", got, want)
+	}
+
+	plan.Reset()
+	if plan.Len() != 0 || plan.String() != "No changes planned.\n" {
+		t.Errorf("Expected empty plan after Reset, got %s", plan.String())
+	}
 }
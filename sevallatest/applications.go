@@ -0,0 +1,474 @@
+package sevallatest
+
+import (
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/juststeveking/sevalla-go"
+)
+
+// AddApplication seeds the server with an application. An ID is assigned
+// when the application has none. The server keeps its own copy, so changes
+// to the returned application do not affect the server.
+func (s *Server) AddApplication(app sevalla.Application) *sevalla.Application {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if app.ID == "" {
+		app.ID = s.newID("app")
+	}
+	if app.CreatedAt.IsZero() {
+		app.CreatedAt = now()
+		app.UpdatedAt = app.CreatedAt
+	}
+	stored := app
+	stored.EnvironmentVars = maps.Clone(app.EnvironmentVars)
+	stored.CustomDomains = slices.Clone(app.CustomDomains)
+	stored.Metadata = maps.Clone(app.Metadata)
+	s.applications = append(s.applications, &stored)
+
+	return &app
+}
+
+// WriteLogs appends runtime log lines to an application
+func (s *Server) WriteLogs(appID string, lines ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.appLogs[appID] = append(s.appLogs[appID], lines...)
+}
+
+// application returns the application with the given ID. Callers must hold
+// s.mu.
+func (s *Server) application(id string) *sevalla.Application {
+	return find(s.applications, func(a *sevalla.Application) bool { return a.ID == id })
+}
+
+// applicationJSON is the API representation of an application, which
+// carries the environment variable values in the clear
+type applicationJSON struct {
+	*sevalla.Application
+	EnvironmentVars map[string]string `json:"environment_variables,omitempty"`
+}
+
+// applicationWire returns the API representation of an application
+func applicationWire(app *sevalla.Application) applicationJSON {
+	return applicationJSON{Application: app, EnvironmentVars: sevalla.RevealSecrets(app.EnvironmentVars)}
+}
+
+func (s *Server) registerApplications(mux *http.ServeMux) {
+	s.handle(mux, "GET /applications", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		writePage(w, r, wireAll(s.applications, applicationWire))
+	})
+
+	s.handle(mux, "POST /applications", func(w http.ResponseWriter, r *http.Request) {
+		var req sevalla.CreateApplicationRequest
+		if !decode(w, r, &req) {
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		if req.Name == "" {
+			writeError(w, http.StatusUnprocessableEntity, "validation_failed", "validation failed",
+				sevalla.ErrorDetail{Field: "name", Code: "required", Message: "name is required"})
+			return
+		}
+		if find(s.applications, func(a *sevalla.Application) bool { return a.Name == req.Name }) != nil {
+			writeError(w, http.StatusConflict, "conflict", fmt.Sprintf("application %q already exists", req.Name))
+			return
+		}
+
+		app := &sevalla.Application{
+			ID:              s.newID("app"),
+			Name:            req.Name,
+			State:           sevalla.StatePending,
+			RepositoryURL:   req.RepositoryURL,
+			Branch:          req.Branch,
+			Region:          req.Region,
+			Plan:            req.Plan,
+			Replicas:        req.Replicas,
+			EnvironmentVars: secrets(req.EnvironmentVars),
+			BuildCommand:    req.BuildCommand,
+			StartCommand:    req.StartCommand,
+			Port:            req.Port,
+			AutoDeploy:      req.AutoDeploy,
+			CDNEnabled:      req.CDNEnabled,
+			SSLEnabled:      req.SSLEnabled,
+			CreatedAt:       now(),
+		}
+		app.UpdatedAt = app.CreatedAt
+		app.URL = fmt.Sprintf("https://%s.sevalla.app", app.Name)
+		if app.Branch == "" {
+			app.Branch = "main"
+		}
+		if app.Region == "" {
+			app.Region = sevalla.RegionUSCentral
+		}
+		if app.Plan == "" {
+			app.Plan = sevalla.PlanHobby
+		}
+		if app.Replicas == 0 {
+			app.Replicas = 1
+		}
+		s.applications = append(s.applications, app)
+
+		writeJSON(w, http.StatusCreated, applicationWire(app))
+	})
+
+	s.handle(mux, "GET /applications/{id}", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		app := s.application(r.PathValue("id"))
+		if app == nil {
+			notFound(w, "application")
+			return
+		}
+
+		writeJSON(w, http.StatusOK, applicationWire(app))
+	})
+
+	s.handle(mux, "PATCH /applications/{id}", func(w http.ResponseWriter, r *http.Request) {
+		var req sevalla.UpdateApplicationRequest
+		if !decode(w, r, &req) {
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		app := s.application(r.PathValue("id"))
+		if app == nil {
+			notFound(w, "application")
+			return
+		}
+
+		if preconditionFailed(w, r, applicationWire(app), app.UpdatedAt) {
+			return
+		}
+
+		if req.Name != nil {
+			app.Name = *req.Name
+		}
+		if req.Branch != nil {
+			app.Branch = *req.Branch
+		}
+		if req.Plan != nil {
+			app.Plan = *req.Plan
+		}
+		if req.Replicas != nil {
+			app.Replicas = *req.Replicas
+		}
+		if req.EnvironmentVars != nil {
+			app.EnvironmentVars = secrets(req.EnvironmentVars)
+		}
+		if req.BuildCommand != nil {
+			app.BuildCommand = *req.BuildCommand
+		}
+		if req.StartCommand != nil {
+			app.StartCommand = *req.StartCommand
+		}
+		if req.Port != nil {
+			app.Port = *req.Port
+		}
+		if req.AutoDeploy != nil {
+			app.AutoDeploy = *req.AutoDeploy
+		}
+		app.UpdatedAt = now()
+
+		writeJSON(w, http.StatusOK, applicationWire(app))
+	})
+
+	s.handle(mux, "DELETE /applications/{id}", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		id := r.PathValue("id")
+		if s.application(id) == nil {
+			notFound(w, "application")
+			return
+		}
+
+		s.applications = filter(s.applications, func(a *sevalla.Application) bool { return a.ID != id })
+		delete(s.appLogs, id)
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	s.handle(mux, "POST /applications/{id}/scale", func(w http.ResponseWriter, r *http.Request) {
+		var req sevalla.ScaleApplicationRequest
+		if !decode(w, r, &req) {
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		app := s.application(r.PathValue("id"))
+		if app == nil {
+			notFound(w, "application")
+			return
+		}
+
+		app.Replicas = req.Replicas
+		if req.Plan != nil {
+			app.Plan = *req.Plan
+		}
+		app.UpdatedAt = now()
+
+		writeJSON(w, http.StatusOK, applicationWire(app))
+	})
+
+	for action, state := range map[string]sevalla.ApplicationState{
+		"start":   sevalla.StateRunning,
+		"restart": sevalla.StateRunning,
+		"stop":    sevalla.StateStopped,
+	} {
+		s.handle(mux, "POST /applications/{id}/"+action, func(w http.ResponseWriter, r *http.Request) {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+
+			app := s.application(r.PathValue("id"))
+			if app == nil {
+				notFound(w, "application")
+				return
+			}
+
+			app.State = state
+			app.UpdatedAt = now()
+			w.WriteHeader(http.StatusAccepted)
+		})
+	}
+
+	s.handle(mux, "POST /applications/{id}/deployments", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		app := s.application(r.PathValue("id"))
+		if app == nil {
+			notFound(w, "application")
+			return
+		}
+
+		writeJSON(w, http.StatusCreated, s.startDeployment(app, nil))
+	})
+
+	s.handle(mux, "GET /applications/{id}/deployments", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		id := r.PathValue("id")
+		if s.application(id) == nil {
+			notFound(w, "application")
+			return
+		}
+
+		writePage(w, r, filter(s.deployments, func(d *sevalla.Deployment) bool { return d.ApplicationID == id }))
+	})
+
+	s.handle(mux, "GET /applications/{id}/deployments/{deploymentID}", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		d := s.deployment(r.PathValue("deploymentID"))
+		if d == nil || d.ApplicationID != r.PathValue("id") {
+			notFound(w, "deployment")
+			return
+		}
+
+		s.advanceDeployment(d)
+		writeJSON(w, http.StatusOK, d)
+	})
+
+	s.handle(mux, "POST /applications/{id}/deployments/{deploymentID}/cancel", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		d := s.deployment(r.PathValue("deploymentID"))
+		if d == nil || d.ApplicationID != r.PathValue("id") {
+			notFound(w, "deployment")
+			return
+		}
+
+		s.cancelDeployment(w, d)
+	})
+
+	s.handle(mux, "POST /applications/{id}/rollback/{deploymentID}", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		app := s.application(r.PathValue("id"))
+		if app == nil {
+			notFound(w, "application")
+			return
+		}
+
+		target := s.deployment(r.PathValue("deploymentID"))
+		if target == nil || target.ApplicationID != app.ID {
+			notFound(w, "deployment")
+			return
+		}
+
+		writeJSON(w, http.StatusCreated, s.startDeployment(app, target))
+	})
+
+	s.handle(mux, "GET /applications/{id}/logs", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		id := r.PathValue("id")
+		if s.application(id) == nil {
+			notFound(w, "application")
+			return
+		}
+
+		writeJSON(w, http.StatusOK, map[string]string{"logs": strings.Join(s.appLogs[id], "\n")})
+	})
+
+	s.handle(mux, "GET /applications/{id}/logs/stream", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		id := r.PathValue("id")
+		if s.application(id) == nil {
+			s.mu.Unlock()
+			notFound(w, "application")
+			return
+		}
+		lines := append([]string(nil), s.appLogs[id]...)
+		s.mu.Unlock()
+
+		writeLogStream(w, r, lines)
+	})
+
+	s.handle(mux, "POST /applications/{id}/domains", func(w http.ResponseWriter, r *http.Request) {
+		var req sevalla.AddDomainRequest
+		if !decode(w, r, &req) {
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		app := s.application(r.PathValue("id"))
+		if app == nil {
+			notFound(w, "application")
+			return
+		}
+
+		app.CustomDomains = append(app.CustomDomains, req.Domain)
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	s.handle(mux, "DELETE /applications/{id}/domains/{domain}", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		app := s.application(r.PathValue("id"))
+		if app == nil {
+			notFound(w, "application")
+			return
+		}
+
+		domain := r.PathValue("domain")
+		domains := app.CustomDomains[:0]
+		for _, d := range app.CustomDomains {
+			if d != domain {
+				domains = append(domains, d)
+			}
+		}
+		app.CustomDomains = domains
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	s.handle(mux, "PUT /applications/{id}/cdn", func(w http.ResponseWriter, r *http.Request) {
+		var req sevalla.CDNSettingsRequest
+		if !decode(w, r, &req) {
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		app := s.application(r.PathValue("id"))
+		if app == nil {
+			notFound(w, "application")
+			return
+		}
+
+		app.CDNEnabled = req.Enabled
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	s.handle(mux, "GET /applications/{id}/usage", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		id := r.PathValue("id")
+		if s.application(id) == nil {
+			notFound(w, "application")
+			return
+		}
+
+		writeJSON(w, http.StatusOK, usage(r, id, ""))
+	})
+
+	s.handle(mux, "GET /applications/{id}/env", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		app := s.application(r.PathValue("id"))
+		if app == nil {
+			notFound(w, "application")
+			return
+		}
+
+		vars := sevalla.RevealSecrets(app.EnvironmentVars)
+		if vars == nil {
+			vars = map[string]string{}
+		}
+		writeJSON(w, http.StatusOK, vars)
+	})
+
+	s.handle(mux, "PUT /applications/{id}/env", func(w http.ResponseWriter, r *http.Request) {
+		var vars map[string]sevalla.Secret
+		if !decode(w, r, &vars) {
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		app := s.application(r.PathValue("id"))
+		if app == nil {
+			notFound(w, "application")
+			return
+		}
+
+		app.EnvironmentVars = vars
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// usage returns empty usage metrics for a resource
+func usage(r *http.Request, appID, dbID string) *sevalla.Usage {
+	period := r.URL.Query().Get("period")
+	if period == "" {
+		period = "day"
+	}
+
+	end := now()
+	return &sevalla.Usage{
+		ApplicationID: appID,
+		DatabaseID:    dbID,
+		Period:        period,
+		StartTime:     end.Add(-24 * time.Hour),
+		EndTime:       end,
+	}
+}
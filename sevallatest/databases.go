@@ -0,0 +1,381 @@
+package sevallatest
+
+import (
+	"fmt"
+	"maps"
+	"net/http"
+
+	"github.com/juststeveking/sevalla-go"
+)
+
+// defaultVersions are the engine versions used when none is requested
+var defaultVersions = map[sevalla.Engine]string{
+	sevalla.EnginePostgreSQL: "16",
+	sevalla.EngineMySQL:      "8.0",
+	sevalla.EngineMongoDB:    "7.0",
+	sevalla.EngineRedis:      "7.2",
+}
+
+// defaultPorts are the ports used in generated connection URLs
+var defaultPorts = map[sevalla.Engine]int{
+	sevalla.EnginePostgreSQL: 5432,
+	sevalla.EngineMySQL:      3306,
+	sevalla.EngineMongoDB:    27017,
+	sevalla.EngineRedis:      6379,
+}
+
+// backupSteps is the order backups move through before completing
+var backupSteps = []string{
+	sevalla.BackupStatusPending,
+	sevalla.BackupStatusInProgress,
+	sevalla.BackupStatusCompleted,
+}
+
+// AddDatabase seeds the server with a database. An ID is assigned when the
+// database has none. The server keeps its own copy, so changes to the
+// returned database do not affect the server.
+func (s *Server) AddDatabase(db sevalla.Database) *sevalla.Database {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if db.ID == "" {
+		db.ID = s.newID("db")
+	}
+	if db.CreatedAt.IsZero() {
+		db.CreatedAt = now()
+		db.UpdatedAt = db.CreatedAt
+	}
+	stored := db
+	stored.Metadata = maps.Clone(db.Metadata)
+	s.databases = append(s.databases, &stored)
+
+	return &db
+}
+
+// database returns the database with the given ID. Callers must hold s.mu.
+func (s *Server) database(id string) *sevalla.Database {
+	return find(s.databases, func(d *sevalla.Database) bool { return d.ID == id })
+}
+
+// backup returns the backup of a database. Callers must hold s.mu.
+func (s *Server) backup(dbID, id string) *sevalla.Backup {
+	return find(s.backups, func(b *sevalla.Backup) bool { return b.DatabaseID == dbID && b.ID == id })
+}
+
+// databaseJSON is the API representation of a database, which carries the
+// credentials in the clear
+type databaseJSON struct {
+	*sevalla.Database
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+}
+
+// databaseWire returns the API representation of a database
+func databaseWire(db *sevalla.Database) databaseJSON {
+	return databaseJSON{Database: db, Username: db.Username.Reveal(), Password: db.Password.Reveal()}
+}
+
+func (s *Server) registerDatabases(mux *http.ServeMux) {
+	s.handle(mux, "GET /databases", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		writePage(w, r, wireAll(s.databases, databaseWire))
+	})
+
+	s.handle(mux, "POST /databases", func(w http.ResponseWriter, r *http.Request) {
+		var req sevalla.CreateDatabaseRequest
+		if !decode(w, r, &req) {
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		var details []sevalla.ErrorDetail
+		if req.Name == "" {
+			details = append(details, sevalla.ErrorDetail{Field: "name", Code: "required", Message: "name is required"})
+		}
+		if _, ok := defaultVersions[req.Type]; !ok {
+			details = append(details, sevalla.ErrorDetail{Field: "type", Code: "invalid", Message: fmt.Sprintf("unsupported engine %q", req.Type)})
+		} else if req.Version != "" && !req.Type.SupportsVersion(req.Version) {
+			details = append(details, sevalla.ErrorDetail{Field: "version", Code: "invalid", Message: fmt.Sprintf("unsupported version %q", req.Version)})
+		}
+		if len(details) > 0 {
+			writeError(w, http.StatusUnprocessableEntity, "validation_failed", "validation failed", details...)
+			return
+		}
+
+		db := &sevalla.Database{
+			ID:         s.newID("db"),
+			Name:       req.Name,
+			Type:       req.Type,
+			Version:    req.Version,
+			Region:     req.Region,
+			Size:       req.Size,
+			Storage:    req.Storage,
+			Backups:    req.Backups,
+			SSLEnabled: req.SSLEnabled,
+			Username:   "sevalla",
+			CreatedAt:  now(),
+		}
+		db.UpdatedAt = db.CreatedAt
+		db.Password = sevalla.Secret("secret-" + db.ID)
+		db.InternalURL = fmt.Sprintf("%s://%s.internal:%d", db.Type, db.ID, defaultPorts[db.Type])
+		if db.Version == "" {
+			db.Version = defaultVersions[db.Type]
+		}
+		if db.Region == "" {
+			db.Region = sevalla.RegionUSCentral
+		}
+		s.databases = append(s.databases, db)
+
+		writeJSON(w, http.StatusCreated, databaseWire(db))
+	})
+
+	s.handle(mux, "GET /databases/{id}", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		db := s.database(r.PathValue("id"))
+		if db == nil {
+			notFound(w, "database")
+			return
+		}
+
+		writeJSON(w, http.StatusOK, databaseWire(db))
+	})
+
+	s.handle(mux, "PATCH /databases/{id}", func(w http.ResponseWriter, r *http.Request) {
+		var req sevalla.UpdateDatabaseRequest
+		if !decode(w, r, &req) {
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		db := s.database(r.PathValue("id"))
+		if db == nil {
+			notFound(w, "database")
+			return
+		}
+
+		if preconditionFailed(w, r, databaseWire(db), db.UpdatedAt) {
+			return
+		}
+
+		if req.Name != nil {
+			db.Name = *req.Name
+		}
+		if req.Size != nil {
+			db.Size = *req.Size
+		}
+		if req.Storage != nil {
+			db.Storage = *req.Storage
+		}
+		if req.Backups != nil {
+			db.Backups = *req.Backups
+		}
+		if req.SSLEnabled != nil {
+			db.SSLEnabled = *req.SSLEnabled
+		}
+		db.UpdatedAt = now()
+
+		writeJSON(w, http.StatusOK, databaseWire(db))
+	})
+
+	s.handle(mux, "DELETE /databases/{id}", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		id := r.PathValue("id")
+		if s.database(id) == nil {
+			notFound(w, "database")
+			return
+		}
+
+		s.databases = filter(s.databases, func(d *sevalla.Database) bool { return d.ID != id })
+		s.backups = filter(s.backups, func(b *sevalla.Backup) bool { return b.DatabaseID != id })
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	s.handle(mux, "GET /databases/{id}/credentials", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		db := s.database(r.PathValue("id"))
+		if db == nil {
+			notFound(w, "database")
+			return
+		}
+
+		writeJSON(w, http.StatusOK, databaseWire(db))
+	})
+
+	s.handle(mux, "POST /databases/{id}/reset-password", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		db := s.database(r.PathValue("id"))
+		if db == nil {
+			notFound(w, "database")
+			return
+		}
+
+		s.nextID["password"]++
+		db.Password = sevalla.Secret(fmt.Sprintf("secret-%s-%d", db.ID, s.nextID["password"]))
+		db.UpdatedAt = now()
+
+		writeJSON(w, http.StatusOK, databaseWire(db))
+	})
+
+	s.handle(mux, "PUT /databases/{id}/public-access", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Enabled bool `json:"enabled"`
+		}
+		if !decode(w, r, &req) {
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		db := s.database(r.PathValue("id"))
+		if db == nil {
+			notFound(w, "database")
+			return
+		}
+
+		db.PublicURL = ""
+		if req.Enabled {
+			db.PublicURL = fmt.Sprintf("%s://%s.sevalla.db:%d", db.Type, db.ID, defaultPorts[db.Type])
+		}
+		db.UpdatedAt = now()
+
+		writeJSON(w, http.StatusOK, databaseWire(db))
+	})
+
+	s.handle(mux, "GET /databases/{id}/usage", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		id := r.PathValue("id")
+		if s.database(id) == nil {
+			notFound(w, "database")
+			return
+		}
+
+		writeJSON(w, http.StatusOK, usage(r, "", id))
+	})
+
+	s.handle(mux, "GET /databases/{id}/backups", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		id := r.PathValue("id")
+		if s.database(id) == nil {
+			notFound(w, "database")
+			return
+		}
+
+		writePage(w, r, filter(s.backups, func(b *sevalla.Backup) bool { return b.DatabaseID == id }))
+	})
+
+	s.handle(mux, "POST /databases/{id}/backups", func(w http.ResponseWriter, r *http.Request) {
+		var req sevalla.CreateBackupRequest
+		if r.ContentLength != 0 && !decode(w, r, &req) {
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		db := s.database(r.PathValue("id"))
+		if db == nil {
+			notFound(w, "database")
+			return
+		}
+
+		backup := &sevalla.Backup{
+			ID:         s.newID("backup"),
+			DatabaseID: db.ID,
+			Type:       req.Type,
+			Status:     sevalla.BackupStatusPending,
+			CreatedAt:  now(),
+		}
+		if backup.Type == "" {
+			backup.Type = "manual"
+		}
+		s.backups = append(s.backups, backup)
+
+		writeJSON(w, http.StatusCreated, backup)
+	})
+
+	s.handle(mux, "GET /databases/{id}/backups/{backupID}", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		backup := s.backup(r.PathValue("id"), r.PathValue("backupID"))
+		if backup == nil {
+			notFound(w, "backup")
+			return
+		}
+
+		// Each fetch moves the backup one step closer to completion
+		for i, step := range backupSteps {
+			if backup.Status == step && i+1 < len(backupSteps) {
+				backup.Status = backupSteps[i+1]
+				break
+			}
+		}
+		if backup.Status == sevalla.BackupStatusCompleted && backup.URL == "" {
+			backup.Size = 1024 * 1024
+			backup.URL = fmt.Sprintf("https://backups.sevalla.test/%s/%s.dump", backup.DatabaseID, backup.ID)
+		}
+
+		writeJSON(w, http.StatusOK, backup)
+	})
+
+	s.handle(mux, "DELETE /databases/{id}/backups/{backupID}", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		dbID, id := r.PathValue("id"), r.PathValue("backupID")
+		if s.backup(dbID, id) == nil {
+			notFound(w, "backup")
+			return
+		}
+
+		s.backups = filter(s.backups, func(b *sevalla.Backup) bool { return b.DatabaseID != dbID || b.ID != id })
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	s.handle(mux, "POST /databases/{id}/restore", func(w http.ResponseWriter, r *http.Request) {
+		var req sevalla.RestoreBackupRequest
+		if !decode(w, r, &req) {
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		dbID := r.PathValue("id")
+		if s.database(dbID) == nil {
+			notFound(w, "database")
+			return
+		}
+
+		backup := s.backup(dbID, req.BackupID)
+		if backup == nil {
+			notFound(w, "backup")
+			return
+		}
+		if backup.Status != sevalla.BackupStatusCompleted {
+			writeError(w, http.StatusConflict, "conflict", fmt.Sprintf("backup is %s", backup.Status))
+			return
+		}
+
+		w.WriteHeader(http.StatusAccepted)
+	})
+}
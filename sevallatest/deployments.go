@@ -0,0 +1,213 @@
+package sevallatest
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/juststeveking/sevalla-go"
+)
+
+// deploymentSteps is the order deployments move through before reaching
+// their final status
+var deploymentSteps = []sevalla.Status{
+	sevalla.StatusQueued,
+	sevalla.StatusBuilding,
+	sevalla.StatusDeploying,
+}
+
+// deployment returns the deployment with the given ID. Callers must hold
+// s.mu.
+func (s *Server) deployment(id string) *sevalla.Deployment {
+	return find(s.deployments, func(d *sevalla.Deployment) bool { return d.ID == id })
+}
+
+// startDeployment queues a new deployment of an application, optionally
+// rolling back to an earlier deployment. Callers must hold s.mu.
+func (s *Server) startDeployment(app *sevalla.Application, rollback *sevalla.Deployment) *sevalla.Deployment {
+	d := s.newDeployment()
+	d.ApplicationID = app.ID
+	d.Branch = app.Branch
+	if rollback != nil {
+		d.Branch = rollback.Branch
+		d.CommitSHA = rollback.CommitSHA
+		d.CommitMessage = rollback.CommitMessage
+	}
+
+	app.State = sevalla.StateDeploying
+	app.LastDeploymentID = d.ID
+
+	return d
+}
+
+// newDeployment creates a queued deployment. Callers must hold s.mu.
+func (s *Server) newDeployment() *sevalla.Deployment {
+	d := &sevalla.Deployment{
+		ID:        s.newID("deploy"),
+		State:     sevalla.StatusQueued,
+		StartedAt: now(),
+		BuildLogs: "queued",
+	}
+	d.CommitSHA = fmt.Sprintf("%040d", s.nextID["deploy"])
+	s.deployments = append(s.deployments, d)
+
+	return d
+}
+
+// advanceDeployment moves a deployment to its next status, finishing with
+// the configured deployment result. Callers must hold s.mu.
+func (s *Server) advanceDeployment(d *sevalla.Deployment) {
+	if d.State.IsTerminal() {
+		return
+	}
+
+	next := s.deployResult
+	for i, step := range deploymentSteps {
+		if d.State == step && i+1 < len(deploymentSteps) {
+			next = deploymentSteps[i+1]
+			break
+		}
+	}
+
+	d.State = next
+	d.BuildLogs += "\n" + string(next)
+
+	if !next.IsTerminal() {
+		return
+	}
+
+	completed := now()
+	d.CompletedAt = &completed
+	d.Duration = int(completed.Sub(d.StartedAt).Seconds())
+	if next == sevalla.StatusFailed {
+		d.ErrorMessage = s.deployMessage
+		if d.ErrorMessage == "" {
+			d.ErrorMessage = "deployment failed"
+		}
+		d.BuildLogs += "\n" + d.ErrorMessage
+	}
+
+	s.finishDeployment(d)
+}
+
+// finishDeployment updates the deployed resource once a deployment reaches
+// a terminal status. Callers must hold s.mu.
+func (s *Server) finishDeployment(d *sevalla.Deployment) {
+	state := sevalla.StateRunning
+	switch d.State {
+	case sevalla.StatusFailed:
+		state = sevalla.StateFailed
+	case sevalla.StatusCancelled:
+		state = sevalla.StateStopped
+	}
+
+	if app := s.application(d.ApplicationID); app != nil && app.LastDeploymentID == d.ID {
+		app.State = state
+		app.UpdatedAt = now()
+	}
+
+	if site := s.staticSite(d.StaticSiteID); site != nil && site.LastDeploymentID == d.ID {
+		site.State = state
+		site.UpdatedAt = now()
+	}
+}
+
+// cancelDeployment cancels a running deployment. Callers must hold s.mu.
+func (s *Server) cancelDeployment(w http.ResponseWriter, d *sevalla.Deployment) {
+	if d.State.IsTerminal() {
+		writeError(w, http.StatusConflict, "conflict", fmt.Sprintf("deployment is already %s", d.State))
+		return
+	}
+
+	completed := now()
+	d.State = sevalla.StatusCancelled
+	d.CompletedAt = &completed
+	s.finishDeployment(d)
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// writeLogStream writes log lines as a Server-Sent Events stream
+func writeLogStream(w http.ResponseWriter, r *http.Request, lines []string) {
+	start, _ := strconv.Atoi(r.Header.Get("Last-Event-ID"))
+	if start < 0 || start > len(lines) {
+		start = 0
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.WriteHeader(http.StatusOK)
+
+	for i := start; i < len(lines); i++ {
+		_, _ = fmt.Fprintf(w, "id: %d\ndata: %s\n\n", i+1, lines[i])
+	}
+	_, _ = fmt.Fprint(w, "event: end\ndata: {}\n\n")
+}
+
+func (s *Server) registerDeployments(mux *http.ServeMux) {
+	s.handle(mux, "GET /deployments", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		writePage(w, r, s.deployments)
+	})
+
+	s.handle(mux, "GET /deployments/{id}", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		d := s.deployment(r.PathValue("id"))
+		if d == nil {
+			notFound(w, "deployment")
+			return
+		}
+
+		s.advanceDeployment(d)
+		writeJSON(w, http.StatusOK, d)
+	})
+
+	s.handle(mux, "GET /deployments/{id}/logs", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		d := s.deployment(r.PathValue("id"))
+		if d == nil {
+			notFound(w, "deployment")
+			return
+		}
+
+		writeJSON(w, http.StatusOK, map[string]string{"logs": d.BuildLogs})
+	})
+
+	s.handle(mux, "GET /deployments/{id}/logs/stream", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		d := s.deployment(r.PathValue("id"))
+		if d == nil {
+			s.mu.Unlock()
+			notFound(w, "deployment")
+			return
+		}
+
+		// Following the logs runs the deployment to completion
+		for !d.State.IsTerminal() {
+			s.advanceDeployment(d)
+		}
+		lines := strings.Split(d.BuildLogs, "\n")
+		s.mu.Unlock()
+
+		writeLogStream(w, r, lines)
+	})
+
+	s.handle(mux, "POST /deployments/{id}/cancel", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		d := s.deployment(r.PathValue("id"))
+		if d == nil {
+			notFound(w, "deployment")
+			return
+		}
+
+		s.cancelDeployment(w, d)
+	})
+}
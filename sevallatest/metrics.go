@@ -0,0 +1,105 @@
+package sevallatest
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/juststeveking/sevalla-go"
+)
+
+// Metrics is an in-memory sevalla.Metrics for asserting on the metrics
+// recorded by a client
+//
+//	metrics := sevallatest.NewMetrics()
+//	client := srv.Client(sevalla.WithMetrics(metrics))
+//	n := metrics.Counter(sevalla.MetricRequests, map[string]string{"service": "Applications", "method": "Get", "status_class": "2xx"})
+type Metrics struct {
+	mu         sync.Mutex
+	counters   map[string]float64
+	gauges     map[string]float64
+	histograms map[string][]float64
+}
+
+// NewMetrics returns an empty in-memory metrics recorder
+func NewMetrics() *Metrics {
+	return &Metrics{
+		counters:   make(map[string]float64),
+		gauges:     make(map[string]float64),
+		histograms: make(map[string][]float64),
+	}
+}
+
+// AddCounter adds value to a counter
+func (m *Metrics) AddCounter(name string, value float64, labels map[string]string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.counters[metricKey(name, labels)] += value
+}
+
+// ObserveHistogram records a value in a histogram
+func (m *Metrics) ObserveHistogram(name string, value float64, labels map[string]string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := metricKey(name, labels)
+	m.histograms[key] = append(m.histograms[key], value)
+}
+
+// SetGauge sets a gauge to value
+func (m *Metrics) SetGauge(name string, value float64, labels map[string]string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.gauges[metricKey(name, labels)] = value
+}
+
+// Counter returns the value of a counter, 0 if it was never incremented
+func (m *Metrics) Counter(name string, labels map[string]string) float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.counters[metricKey(name, labels)]
+}
+
+// Gauge returns the value of a gauge and whether it was ever set
+func (m *Metrics) Gauge(name string, labels map[string]string) (float64, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	v, ok := m.gauges[metricKey(name, labels)]
+	return v, ok
+}
+
+// Observations returns the values recorded in a histogram
+func (m *Metrics) Observations(name string, labels map[string]string) []float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]float64(nil), m.histograms[metricKey(name, labels)]...)
+}
+
+// Reset discards all recorded metrics
+func (m *Metrics) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	clear(m.counters)
+	clear(m.gauges)
+	clear(m.histograms)
+}
+
+// metricKey identifies a metric by name and sorted labels
+func metricKey(name string, labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for k, v := range labels {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+
+	return name + "{" + strings.Join(pairs, ",") + "}"
+}
+
+// Compile-time assertion that Metrics implements sevalla.Metrics
+var _ sevalla.Metrics = (*Metrics)(nil)
@@ -0,0 +1,109 @@
+package sevallatest
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/juststeveking/sevalla-go"
+)
+
+func TestMetrics_RecordsClientMetrics(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	srv.InjectFault("GET /applications", Fault{
+		StatusCode: http.StatusServiceUnavailable,
+		Header: http.Header{
+			"X-Ratelimit-Limit":     {"100"},
+			"X-Ratelimit-Remaining": {"7"},
+		},
+		Count: 1,
+	})
+
+	policy := sevalla.DefaultRetryPolicy()
+	policy.BaseDelay = time.Millisecond
+
+	metrics := NewMetrics()
+	client := srv.Client(sevalla.WithRetryPolicy(policy), sevalla.WithMetrics(metrics))
+
+	if _, _, err := client.Applications.List(context.Background(), nil); err != nil {
+		t.Fatalf("Applications.List returned error: %v", err)
+	}
+
+	list := map[string]string{sevalla.LabelService: "Applications", sevalla.LabelMethod: "List"}
+	withClass := func(class string) map[string]string {
+		return map[string]string{sevalla.LabelService: "Applications", sevalla.LabelMethod: "List", sevalla.LabelStatusClass: class}
+	}
+
+	if got := metrics.Counter(sevalla.MetricRequests, withClass("5xx")); got != 1 {
+		t.Errorf("Expected 1 5xx request, got %v", got)
+	}
+	if got := metrics.Counter(sevalla.MetricRequests, withClass("2xx")); got != 1 {
+		t.Errorf("Expected 1 2xx request, got %v", got)
+	}
+	if got := metrics.Counter(sevalla.MetricRequestErrors, withClass("5xx")); got != 1 {
+		t.Errorf("Expected 1 error, got %v", got)
+	}
+	if got := metrics.Counter(sevalla.MetricRetries, list); got != 1 {
+		t.Errorf("Expected 1 retry, got %v", got)
+	}
+	if got := metrics.Observations(sevalla.MetricRequestDuration, list); len(got) != 2 {
+		t.Errorf("Expected 2 latency observations, got %v", got)
+	}
+	if got, ok := metrics.Gauge(sevalla.MetricRateLimitRemaining, nil); !ok || got != 7 {
+		t.Errorf("Expected rate limit remaining 7, got %v (set %v)", got, ok)
+	}
+
+	app, _, err := client.Applications.Create(context.Background(), &sevalla.CreateApplicationRequest{Name: "web"})
+	if err != nil {
+		t.Fatalf("Applications.Create returned error: %v", err)
+	}
+	if _, _, err := client.Applications.Get(context.Background(), app.ID); err != nil {
+		t.Fatalf("Applications.Get returned error: %v", err)
+	}
+
+	get := map[string]string{sevalla.LabelService: "Applications", sevalla.LabelMethod: "Get", sevalla.LabelStatusClass: "2xx"}
+	if got := metrics.Counter(sevalla.MetricRequests, get); got != 1 {
+		t.Errorf("Expected Get to be labelled by method rather than URL, got %v", got)
+	}
+
+	metrics.Reset()
+	if got := metrics.Counter(sevalla.MetricRequests, get); got != 0 {
+		t.Errorf("Expected counters to be reset, got %v", got)
+	}
+}
+
+func TestMetrics_RateLimitRemainingWithoutLimit(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	srv.InjectFault("GET /applications", Fault{
+		StatusCode: http.StatusServiceUnavailable,
+		Header: http.Header{
+			"X-Ratelimit-Remaining": {"3"},
+			"X-Ratelimit-Reset":     {strconv.FormatInt(time.Now().Add(time.Minute).Unix(), 10)},
+		},
+		Count: 1,
+	})
+
+	metrics := NewMetrics()
+	client := srv.Client(sevalla.WithMetrics(metrics))
+
+	if _, _, err := client.Applications.List(context.Background(), nil); !sevalla.IsServerError(err) {
+		t.Fatalf("Expected the injected 503, got %v", err)
+	}
+	if got, ok := metrics.Gauge(sevalla.MetricRateLimitRemaining, nil); !ok || got != 3 {
+		t.Errorf("Expected rate limit remaining 3 without X-RateLimit-Limit, got %v (set %v)", got, ok)
+	}
+
+	metrics.Reset()
+	if _, _, err := client.Applications.List(context.Background(), nil); err != nil {
+		t.Fatalf("Applications.List returned error: %v", err)
+	}
+	if _, ok := metrics.Gauge(sevalla.MetricRateLimitRemaining, nil); ok {
+		t.Error("Expected no gauge for a response without X-RateLimit-Remaining")
+	}
+}
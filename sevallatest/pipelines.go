@@ -0,0 +1,318 @@
+package sevallatest
+
+import (
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/juststeveking/sevalla-go"
+)
+
+// AddPipeline seeds the server with a pipeline. An ID is assigned when the
+// pipeline has none. The server keeps its own copy, so changes to the
+// returned pipeline do not affect the server.
+func (s *Server) AddPipeline(pipeline sevalla.Pipeline) *sevalla.Pipeline {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if pipeline.ID == "" {
+		pipeline.ID = s.newID("pipe")
+	}
+	if pipeline.CreatedAt.IsZero() {
+		pipeline.CreatedAt = now()
+		pipeline.UpdatedAt = pipeline.CreatedAt
+	}
+	stored := pipeline
+	stored.Steps = slices.Clone(pipeline.Steps)
+	for i := range stored.Steps {
+		stored.Steps[i].DependsOn = slices.Clone(stored.Steps[i].DependsOn)
+	}
+	stored.Environment = maps.Clone(pipeline.Environment)
+	stored.Metadata = maps.Clone(pipeline.Metadata)
+	s.pipelines = append(s.pipelines, &stored)
+
+	return &pipeline
+}
+
+// pipeline returns the pipeline with the given ID. Callers must hold s.mu.
+func (s *Server) pipeline(id string) *sevalla.Pipeline {
+	return find(s.pipelines, func(p *sevalla.Pipeline) bool { return p.ID == id })
+}
+
+// run returns the run of a pipeline. Callers must hold s.mu.
+func (s *Server) run(pipelineID, id string) *sevalla.PipelineRun {
+	return find(s.runs, func(r *sevalla.PipelineRun) bool { return r.PipelineID == pipelineID && r.ID == id })
+}
+
+// startRun queues a new run of a pipeline. Callers must hold s.mu.
+func (s *Server) startRun(p *sevalla.Pipeline) *sevalla.PipelineRun {
+	run := &sevalla.PipelineRun{
+		ID:         s.newID("run"),
+		PipelineID: p.ID,
+		State:      sevalla.StatusQueued,
+		Branch:     p.Branch,
+		StartedAt:  now(),
+	}
+	for _, step := range p.Steps {
+		run.Steps = append(run.Steps, sevalla.PipelineRunStep{Name: step.Name, State: sevalla.StatusQueued})
+	}
+	s.runs = append(s.runs, run)
+
+	return run
+}
+
+// advanceRun moves a run to its next status, finishing with the configured
+// deployment result. Callers must hold s.mu.
+func (s *Server) advanceRun(run *sevalla.PipelineRun) {
+	if run.State.IsTerminal() {
+		return
+	}
+
+	if run.State == sevalla.StatusQueued {
+		run.State = sevalla.StatusBuilding
+		return
+	}
+
+	completed := now()
+	run.State = s.deployResult
+	run.CompletedAt = &completed
+	run.Duration = int(completed.Sub(run.StartedAt).Seconds())
+
+	for i := range run.Steps {
+		run.Steps[i].State = sevalla.StatusSuccess
+		run.Steps[i].Output = run.Steps[i].Name + " finished"
+	}
+	if run.State == sevalla.StatusFailed && len(run.Steps) > 0 {
+		last := &run.Steps[len(run.Steps)-1]
+		last.State = sevalla.StatusFailed
+		last.ErrorMessage = s.deployMessage
+		if last.ErrorMessage == "" {
+			last.ErrorMessage = "step failed"
+		}
+	}
+}
+
+// pipelineJSON is the API representation of a pipeline, which carries the
+// environment values in the clear
+type pipelineJSON struct {
+	*sevalla.Pipeline
+	Environment map[string]string `json:"environment,omitempty"`
+}
+
+// pipelineWire returns the API representation of a pipeline
+func pipelineWire(p *sevalla.Pipeline) pipelineJSON {
+	return pipelineJSON{Pipeline: p, Environment: sevalla.RevealSecrets(p.Environment)}
+}
+
+func (s *Server) registerPipelines(mux *http.ServeMux) {
+	s.handle(mux, "GET /pipelines", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		writePage(w, r, wireAll(s.pipelines, pipelineWire))
+	})
+
+	s.handle(mux, "POST /pipelines", func(w http.ResponseWriter, r *http.Request) {
+		var req sevalla.CreatePipelineRequest
+		if !decode(w, r, &req) {
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		if req.Name == "" {
+			writeError(w, http.StatusUnprocessableEntity, "validation_failed", "validation failed",
+				sevalla.ErrorDetail{Field: "name", Code: "required", Message: "name is required"})
+			return
+		}
+
+		p := &sevalla.Pipeline{
+			ID:          s.newID("pipe"),
+			Name:        req.Name,
+			Enabled:     req.Enabled,
+			Trigger:     req.Trigger,
+			Branch:      req.Branch,
+			Steps:       req.Steps,
+			Environment: secrets(req.Environment),
+			Metadata:    req.Metadata,
+			CreatedAt:   now(),
+		}
+		p.UpdatedAt = p.CreatedAt
+		s.pipelines = append(s.pipelines, p)
+
+		writeJSON(w, http.StatusCreated, pipelineWire(p))
+	})
+
+	s.handle(mux, "GET /pipelines/{id}", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		p := s.pipeline(r.PathValue("id"))
+		if p == nil {
+			notFound(w, "pipeline")
+			return
+		}
+
+		writeJSON(w, http.StatusOK, pipelineWire(p))
+	})
+
+	s.handle(mux, "PUT /pipelines/{id}", func(w http.ResponseWriter, r *http.Request) {
+		var req sevalla.UpdatePipelineRequest
+		if !decode(w, r, &req) {
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		p := s.pipeline(r.PathValue("id"))
+		if p == nil {
+			notFound(w, "pipeline")
+			return
+		}
+
+		if preconditionFailed(w, r, pipelineWire(p), p.UpdatedAt) {
+			return
+		}
+
+		if req.Name != nil {
+			p.Name = *req.Name
+		}
+		if req.Enabled != nil {
+			p.Enabled = *req.Enabled
+		}
+		if req.Trigger != nil {
+			p.Trigger = *req.Trigger
+		}
+		if req.Branch != nil {
+			p.Branch = *req.Branch
+		}
+		if req.Steps != nil {
+			p.Steps = req.Steps
+		}
+		if req.Environment != nil {
+			p.Environment = secrets(req.Environment)
+		}
+		if req.Metadata != nil {
+			p.Metadata = req.Metadata
+		}
+		p.UpdatedAt = now()
+
+		writeJSON(w, http.StatusOK, pipelineWire(p))
+	})
+
+	s.handle(mux, "DELETE /pipelines/{id}", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		id := r.PathValue("id")
+		if s.pipeline(id) == nil {
+			notFound(w, "pipeline")
+			return
+		}
+
+		s.pipelines = filter(s.pipelines, func(p *sevalla.Pipeline) bool { return p.ID != id })
+		s.runs = filter(s.runs, func(run *sevalla.PipelineRun) bool { return run.PipelineID != id })
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	s.handle(mux, "POST /pipelines/{id}/runs", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		p := s.pipeline(r.PathValue("id"))
+		if p == nil {
+			notFound(w, "pipeline")
+			return
+		}
+
+		writeJSON(w, http.StatusCreated, s.startRun(p))
+	})
+
+	s.handle(mux, "GET /pipelines/{id}/runs", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		id := r.PathValue("id")
+		if s.pipeline(id) == nil {
+			notFound(w, "pipeline")
+			return
+		}
+
+		writePage(w, r, filter(s.runs, func(run *sevalla.PipelineRun) bool { return run.PipelineID == id }))
+	})
+
+	s.handle(mux, "GET /pipelines/{id}/runs/{runID}", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		run := s.run(r.PathValue("id"), r.PathValue("runID"))
+		if run == nil {
+			notFound(w, "pipeline run")
+			return
+		}
+
+		s.advanceRun(run)
+		writeJSON(w, http.StatusOK, run)
+	})
+
+	s.handle(mux, "POST /pipelines/{id}/runs/{runID}/cancel", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		run := s.run(r.PathValue("id"), r.PathValue("runID"))
+		if run == nil {
+			notFound(w, "pipeline run")
+			return
+		}
+		if run.State.IsTerminal() {
+			writeError(w, http.StatusConflict, "conflict", fmt.Sprintf("pipeline run is already %s", run.State))
+			return
+		}
+
+		completed := now()
+		run.State = sevalla.StatusCancelled
+		run.CompletedAt = &completed
+		w.WriteHeader(http.StatusAccepted)
+	})
+
+	s.handle(mux, "GET /pipelines/{id}/runs/{runID}/logs", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		run := s.run(r.PathValue("id"), r.PathValue("runID"))
+		if run == nil {
+			notFound(w, "pipeline run")
+			return
+		}
+
+		var lines []string
+		for _, step := range run.Steps {
+			lines = append(lines, fmt.Sprintf("[%s] %s", step.Name, step.State))
+			if step.ErrorMessage != "" {
+				lines = append(lines, fmt.Sprintf("[%s] %s", step.Name, step.ErrorMessage))
+			}
+		}
+		writeJSON(w, http.StatusOK, map[string]string{"logs": strings.Join(lines, "\n")})
+	})
+
+	s.handle(mux, "POST /pipelines/{id}/runs/{runID}/retry", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		p := s.pipeline(r.PathValue("id"))
+		run := s.run(r.PathValue("id"), r.PathValue("runID"))
+		if p == nil || run == nil {
+			notFound(w, "pipeline run")
+			return
+		}
+
+		retry := s.startRun(p)
+		retry.Branch = run.Branch
+		retry.CommitSHA = run.CommitSHA
+		writeJSON(w, http.StatusCreated, retry)
+	})
+}
@@ -0,0 +1,440 @@
+package sevallatest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/juststeveking/sevalla-go"
+)
+
+// RecordMode selects whether a Recorder replays a cassette or records one
+type RecordMode int
+
+const (
+	// ModeReplay answers requests from the cassette without network access.
+	// It is the default, so tests run offline unless asked to record.
+	ModeReplay RecordMode = iota
+
+	// ModeRecord sends requests to the API and writes the cassette on
+	// Close, replacing any previous recording
+	ModeRecord
+
+	// ModeRecordOnce replays the cassette if it exists, and records it
+	// otherwise
+	ModeRecordOnce
+)
+
+// MatchMode selects how a replaying Recorder matches requests to the
+// recorded interactions. Requests match an interaction when their method,
+// path, query and body are the same, bodies being compared after scrubbing.
+type MatchMode int
+
+const (
+	// MatchStrict replays each interaction once, in the recorded order. A
+	// request that does not match the next interaction is an error, and
+	// Close reports interactions that were not replayed.
+	MatchStrict MatchMode = iota
+
+	// MatchLenient replays the first matching interaction not replayed yet,
+	// in any order. Once all matching interactions have been replayed, the
+	// last one is replayed again, so polling loops can run longer than when
+	// they were recorded.
+	MatchLenient
+)
+
+// ErrUnrecorded is matched by the error returned for a request that no
+// recorded interaction matches
+var ErrUnrecorded = errors.New("sevallatest: request not recorded")
+
+// RecorderOptions configures a Recorder
+type RecorderOptions struct {
+	// Mode selects replaying or recording, ModeReplay by default
+	Mode RecordMode
+
+	// Matching selects how requests are matched when replaying,
+	// MatchStrict by default
+	Matching MatchMode
+
+	// Transport sends requests when recording, http.DefaultTransport when
+	// nil
+	Transport http.RoundTripper
+
+	// Scrub is called for every recorded interaction, and every request
+	// being matched, after the built-in scrubbing. Use it to remove other
+	// sensitive values, such as IDs or hostnames.
+	Scrub func(*Interaction)
+}
+
+// Interaction is a request and its response, as stored in a cassette
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is the part of a request used for matching. Headers are
+// not recorded, so credentials never reach the cassette.
+type RecordedRequest struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	Query  string `json:"query,omitempty"`
+	Body   string `json:"body,omitempty"`
+}
+
+// String returns the method, path and query of the request
+func (r RecordedRequest) String() string {
+	if r.Query == "" {
+		return r.Method + " " + r.Path
+	}
+	return r.Method + " " + r.Path + "?" + r.Query
+}
+
+// RecordedResponse is a recorded response
+type RecordedResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// cassette is the file format of a recording
+type cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// UnrecordedError is returned for a request that no recorded interaction
+// matches. It matches ErrUnrecorded.
+type UnrecordedError struct {
+	// Cassette is the path of the cassette file
+	Cassette string
+
+	// Request is the scrubbed request
+	Request RecordedRequest
+
+	// Expected is the next recorded request in strict mode, nil when all
+	// interactions have been replayed or matching is lenient
+	Expected *RecordedRequest
+}
+
+// Error returns the unrecorded request error message
+func (e *UnrecordedError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "sevallatest: no interaction in %s matches %s", e.Cassette, e.Request)
+	if e.Request.Body != "" {
+		fmt.Fprintf(&b, " with body %s", e.Request.Body)
+	}
+
+	switch {
+	case e.Expected != nil:
+		fmt.Fprintf(&b, ", expected %s", e.Expected)
+		if e.Expected.Body != "" {
+			fmt.Fprintf(&b, " with body %s", e.Expected.Body)
+		}
+	default:
+		b.WriteString(", all interactions have been replayed")
+	}
+
+	b.WriteString(" (record the cassette again with ModeRecord)")
+	return b.String()
+}
+
+// Is reports whether target is ErrUnrecorded
+func (e *UnrecordedError) Is(target error) bool {
+	return target == ErrUnrecorded
+}
+
+// Recorder is an http.RoundTripper that records API interactions into a
+// cassette file and replays them, so tests written against the real API can
+// run without network access. Recorded bodies have database credentials and
+// environment variable values replaced with "[REDACTED]", and no request
+// headers are recorded.
+//
+//	rec, err := sevallatest.NewRecorder("testdata/applications.json", nil)
+//	if err != nil {
+//		t.Fatal(err)
+//	}
+//	defer func() {
+//		if err := rec.Close(); err != nil {
+//			t.Error(err)
+//		}
+//	}()
+//
+//	client := rec.Client(sevalla.WithAPIKey(os.Getenv("SEVALLA_API_KEY")))
+type Recorder struct {
+	path      string
+	recording bool
+	matching  MatchMode
+	transport http.RoundTripper
+	scrub     func(*Interaction)
+
+	mu           sync.Mutex
+	interactions []Interaction
+	replayed     []bool
+	next         int
+}
+
+// NewRecorder creates a Recorder for the given cassette file. Replaying
+// requires the file to exist. Nil options select ModeReplay and
+// MatchStrict.
+func NewRecorder(path string, opts *RecorderOptions) (*Recorder, error) {
+	if opts == nil {
+		opts = &RecorderOptions{}
+	}
+
+	r := &Recorder{
+		path:      path,
+		matching:  opts.Matching,
+		transport: opts.Transport,
+		scrub:     opts.Scrub,
+	}
+	if r.transport == nil {
+		r.transport = http.DefaultTransport
+	}
+
+	switch opts.Mode {
+	case ModeRecord:
+		r.recording = true
+		return r, nil
+	case ModeRecordOnce:
+		if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+			r.recording = true
+			return r, nil
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("sevallatest: reading cassette: %w", err)
+	}
+
+	var c cassette
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("sevallatest: reading cassette %s: %w", path, err)
+	}
+
+	r.interactions = c.Interactions
+	r.replayed = make([]bool, len(c.Interactions))
+
+	return r, nil
+}
+
+// Recording reports whether the recorder sends requests and records them,
+// rather than replaying the cassette
+func (r *Recorder) Recording() bool {
+	return r.recording
+}
+
+// Client returns a sevalla.Client sending its requests through the
+// recorder. Additional options are applied after the HTTP client, so
+// WithHTTPClient must not be among them.
+func (r *Recorder) Client(opts ...sevalla.ClientOption) *sevalla.Client {
+	opts = append([]sevalla.ClientOption{
+		sevalla.WithHTTPClient(&http.Client{Transport: r}),
+	}, opts...)
+
+	return sevalla.NewClient(opts...)
+}
+
+// RoundTrip records or replays a request
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if r.recording {
+		return r.record(req, body)
+	}
+
+	return r.replay(req, body)
+}
+
+// record sends a request and appends the scrubbed interaction to the
+// cassette. The caller gets the unscrubbed response.
+func (r *Recorder) record(req *http.Request, body []byte) (*http.Response, error) {
+	out := req.Clone(req.Context())
+	out.Body = nil
+	if body != nil {
+		out.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	resp, err := r.transport.RoundTrip(out)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	header := resp.Header.Clone()
+	header.Del("Set-Cookie")
+
+	interaction := Interaction{
+		Request: recordedRequest(req, body),
+		Response: RecordedResponse{
+			StatusCode: resp.StatusCode,
+			Header:     header,
+			Body:       scrubBody(req.URL.Path, data),
+		},
+	}
+	if r.scrub != nil {
+		r.scrub(&interaction)
+	}
+
+	r.mu.Lock()
+	r.interactions = append(r.interactions, interaction)
+	r.mu.Unlock()
+
+	resp.Body = io.NopCloser(bytes.NewReader(data))
+	return resp, nil
+}
+
+// replay answers a request from the cassette
+func (r *Recorder) replay(req *http.Request, body []byte) (*http.Response, error) {
+	interaction := Interaction{Request: recordedRequest(req, body)}
+	if r.scrub != nil {
+		r.scrub(&interaction)
+	}
+	recorded := interaction.Request
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	match := -1
+	switch r.matching {
+	case MatchLenient:
+		for i := range r.interactions {
+			if !matches(r.interactions[i].Request, recorded) {
+				continue
+			}
+			match = i
+			if !r.replayed[i] {
+				break
+			}
+		}
+	default:
+		if r.next < len(r.interactions) && matches(r.interactions[r.next].Request, recorded) {
+			match = r.next
+			r.next++
+		}
+	}
+
+	if match < 0 {
+		err := &UnrecordedError{Cassette: r.path, Request: recorded}
+		if r.matching == MatchStrict && r.next < len(r.interactions) {
+			err.Expected = &r.interactions[r.next].Request
+		}
+		return nil, err
+	}
+
+	r.replayed[match] = true
+	recordedResp := r.interactions[match].Response
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", recordedResp.StatusCode, http.StatusText(recordedResp.StatusCode)),
+		StatusCode:    recordedResp.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        recordedResp.Header.Clone(),
+		Body:          io.NopCloser(strings.NewReader(recordedResp.Body)),
+		ContentLength: int64(len(recordedResp.Body)),
+		Request:       req,
+	}, nil
+}
+
+// Close writes the cassette when recording. When replaying with
+// MatchStrict, it returns an error if some interactions were not replayed.
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.recording {
+		if r.matching == MatchStrict && r.next < len(r.interactions) {
+			return fmt.Errorf("sevallatest: %d of %d interactions in %s were not replayed, starting with %s",
+				len(r.interactions)-r.next, len(r.interactions), r.path, r.interactions[r.next].Request)
+		}
+		return nil
+	}
+
+	data, err := json.MarshalIndent(cassette{Interactions: r.interactions}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return fmt.Errorf("sevallatest: writing cassette: %w", err)
+	}
+	if err := os.WriteFile(r.path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("sevallatest: writing cassette: %w", err)
+	}
+
+	return nil
+}
+
+// recordedRequest returns the scrubbed, matchable form of a request
+func recordedRequest(req *http.Request, body []byte) RecordedRequest {
+	return RecordedRequest{
+		Method: req.Method,
+		Path:   req.URL.Path,
+		Query:  req.URL.Query().Encode(),
+		Body:   scrubBody(req.URL.Path, body),
+	}
+}
+
+// matches reports whether a request matches a recorded one. JSON bodies
+// are compared by value, so formatting and key order do not matter.
+func matches(recorded, req RecordedRequest) bool {
+	if recorded.Method != req.Method || recorded.Path != req.Path || recorded.Query != req.Query {
+		return false
+	}
+	if recorded.Body == req.Body {
+		return true
+	}
+
+	var a, b interface{}
+	if json.Unmarshal([]byte(recorded.Body), &a) != nil || json.Unmarshal([]byte(req.Body), &b) != nil {
+		return false
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// scrubBody redacts database credentials and environment variable values
+// in a JSON body, as the client does in debug logs. Bodies of the
+// environment variable endpoints hold nothing but variables, so all of
+// their values are redacted. Other bodies are returned as they are.
+func scrubBody(path string, body []byte) string {
+	body = bytes.TrimSpace(body)
+	if len(body) == 0 || !json.Valid(body) {
+		return string(body)
+	}
+
+	if strings.HasSuffix(path, "/env") {
+		var vars map[string]interface{}
+		if err := json.Unmarshal(body, &vars); err == nil {
+			for key := range vars {
+				vars[key] = "[REDACTED]"
+			}
+			if data, err := json.Marshal(vars); err == nil {
+				return string(data)
+			}
+		}
+	}
+
+	return sevalla.Redact(json.RawMessage(body)).String()
+}
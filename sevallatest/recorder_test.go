@@ -0,0 +1,190 @@
+package sevallatest
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/juststeveking/sevalla-go"
+)
+
+func TestRecorder_RecordAndReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "testdata", "applications.json")
+	ctx := context.Background()
+
+	srv := NewServer()
+	rec, err := NewRecorder(path, &RecorderOptions{Mode: ModeRecord})
+	if err != nil {
+		t.Fatalf("NewRecorder returned error: %v", err)
+	}
+
+	client := rec.Client(sevalla.WithAPIKey("live-key"), sevalla.WithBaseURL(srv.URL))
+	created, _, err := client.Applications.Create(ctx, &sevalla.CreateApplicationRequest{
+		Name:            "web",
+		EnvironmentVars: map[string]string{"API_KEY": "live-secret"},
+	})
+	if err != nil {
+		t.Fatalf("Applications.Create returned error: %v", err)
+	}
+	if created.EnvironmentVars["API_KEY"].Reveal() != "live-secret" {
+		t.Errorf("Expected unscrubbed response while recording, got %q", created.EnvironmentVars["API_KEY"].Reveal())
+	}
+	db, _, err := client.Databases.Create(ctx, &sevalla.CreateDatabaseRequest{Name: "main", Type: sevalla.EnginePostgreSQL})
+	if err != nil {
+		t.Fatalf("Databases.Create returned error: %v", err)
+	}
+	if _, _, err := client.Databases.GetCredentials(ctx, db.ID); err != nil {
+		t.Fatalf("Databases.GetCredentials returned error: %v", err)
+	}
+
+	if err := rec.Close(); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+	srv.Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read cassette: %v", err)
+	}
+	for _, secret := range []string{"live-key", "live-secret", "secret-" + db.ID} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("Expected %q to be scrubbed from the cassette: %s", secret, data)
+		}
+	}
+
+	rec, err = NewRecorder(path, nil)
+	if err != nil {
+		t.Fatalf("NewRecorder returned error: %v", err)
+	}
+
+	client = rec.Client(sevalla.WithBaseURL(srv.URL))
+	replayed, _, err := client.Applications.Create(ctx, &sevalla.CreateApplicationRequest{
+		Name:            "web",
+		EnvironmentVars: map[string]string{"API_KEY": "other-secret"},
+	})
+	if err != nil {
+		t.Fatalf("Applications.Create returned error when replaying: %v", err)
+	}
+	if replayed.ID != created.ID || replayed.EnvironmentVars["API_KEY"].Reveal() != "[REDACTED]" {
+		t.Errorf("Unexpected replayed application: %+v", replayed)
+	}
+	if _, _, err := client.Databases.Create(ctx, &sevalla.CreateDatabaseRequest{Name: "main", Type: sevalla.EnginePostgreSQL}); err != nil {
+		t.Fatalf("Databases.Create returned error when replaying: %v", err)
+	}
+	creds, _, err := client.Databases.GetCredentials(ctx, db.ID)
+	if err != nil {
+		t.Fatalf("Databases.GetCredentials returned error when replaying: %v", err)
+	}
+	if creds.Password.Reveal() != "[REDACTED]" {
+		t.Errorf("Expected scrubbed password, got %q", creds.Password.Reveal())
+	}
+
+	if err := rec.Close(); err != nil {
+		t.Errorf("Close returned error: %v", err)
+	}
+}
+
+// writeCassette writes a cassette with a single application lookup
+func writeCassette(t *testing.T) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	cassette := `{
+  "interactions": [
+    {
+      "request": {"method": "GET", "path": "/applications/app-1"},
+      "response": {"status_code": 200, "header": {"Content-Type": ["application/json"]}, "body": "{\"id\":\"app-1\",\"name\":\"web\"}"}
+    },
+    {
+      "request": {"method": "GET", "path": "/applications", "query": "page=2"},
+      "response": {"status_code": 200, "header": {"Content-Type": ["application/json"]}, "body": "[]"}
+    }
+  ]
+}`
+	if err := os.WriteFile(path, []byte(cassette), 0o644); err != nil {
+		t.Fatalf("Failed to write cassette: %v", err)
+	}
+
+	return path
+}
+
+func TestRecorder_Strict(t *testing.T) {
+	rec, err := NewRecorder(writeCassette(t), nil)
+	if err != nil {
+		t.Fatalf("NewRecorder returned error: %v", err)
+	}
+
+	client := rec.Client()
+	ctx := context.Background()
+
+	_, _, err = client.Applications.List(ctx, &sevalla.ListOptions{Page: 2})
+	var unrecorded *UnrecordedError
+	if !errors.Is(err, ErrUnrecorded) || !errors.As(err, &unrecorded) {
+		t.Fatalf("Expected unrecorded request error, got %v", err)
+	}
+	if unrecorded.Expected == nil || unrecorded.Expected.String() != "GET /applications/app-1" {
+		t.Errorf("Expected next interaction in error, got %v", unrecorded)
+	}
+
+	app, _, err := client.Applications.Get(ctx, "app-1")
+	if err != nil {
+		t.Fatalf("Applications.Get returned error: %v", err)
+	}
+	if app.Name != "web" {
+		t.Errorf("Expected name 'web', got %s", app.Name)
+	}
+
+	if _, _, err := client.Applications.Get(ctx, "app-1"); !errors.Is(err, ErrUnrecorded) {
+		t.Errorf("Expected a second lookup to be unrecorded, got %v", err)
+	}
+
+	if err := rec.Close(); err == nil || !strings.Contains(err.Error(), "GET /applications?page=2") {
+		t.Errorf("Expected Close to report the interaction not replayed, got %v", err)
+	}
+}
+
+func TestRecorder_Lenient(t *testing.T) {
+	rec, err := NewRecorder(writeCassette(t), &RecorderOptions{Matching: MatchLenient})
+	if err != nil {
+		t.Fatalf("NewRecorder returned error: %v", err)
+	}
+
+	client := rec.Client()
+	ctx := context.Background()
+
+	if _, _, err := client.Applications.List(ctx, &sevalla.ListOptions{Page: 2}); err != nil {
+		t.Fatalf("Applications.List returned error: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		if _, _, err := client.Applications.Get(ctx, "app-1"); err != nil {
+			t.Fatalf("Applications.Get returned error: %v", err)
+		}
+	}
+
+	if _, _, err := client.Applications.Get(ctx, "app-2"); !errors.Is(err, ErrUnrecorded) {
+		t.Errorf("Expected unrecorded request error, got %v", err)
+	}
+
+	if err := rec.Close(); err != nil {
+		t.Errorf("Close returned error: %v", err)
+	}
+}
+
+func TestNewRecorder_MissingCassette(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing.json")
+
+	if _, err := NewRecorder(path, nil); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected missing cassette error, got %v", err)
+	}
+
+	rec, err := NewRecorder(path, &RecorderOptions{Mode: ModeRecordOnce})
+	if err != nil {
+		t.Fatalf("NewRecorder returned error: %v", err)
+	}
+	if !rec.Recording() {
+		t.Error("Expected ModeRecordOnce to record a missing cassette")
+	}
+}
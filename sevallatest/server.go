@@ -0,0 +1,476 @@
+// Package sevallatest provides an in-memory fake of the Sevalla API for
+// testing code built on the sevalla package without network access.
+//
+// The fake keeps state between requests: created resources can be fetched,
+// listed, updated and deleted, deployments and pipeline runs move through
+// their statuses each time they are fetched, and errors or latency can be
+// injected per endpoint. POST requests repeated with the same
+// Idempotency-Key get the stored response replayed, GET responses carry an
+// ETag honoured through If-None-Match, and updates honour If-Match and
+// If-Unmodified-Since.
+//
+//	srv := sevallatest.NewServer()
+//	defer srv.Close()
+//
+//	client := srv.Client()
+//	app, _, err := client.Applications.Create(ctx, &sevalla.CreateApplicationRequest{Name: "web"})
+//
+// Recorder records calls to the real API into cassette files and replays
+// them, for tests that need real responses but no network access.
+package sevallatest
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/juststeveking/sevalla-go"
+)
+
+// Fault describes an error or delay injected into an endpoint
+type Fault struct {
+	// StatusCode is the HTTP status returned instead of the normal
+	// response. Zero only applies Latency.
+	StatusCode int
+
+	// Code and Message populate the error response body
+	Code    string
+	Message string
+
+	// Header holds extra response headers, such as Retry-After
+	Header http.Header
+
+	// Latency delays the response
+	Latency time.Duration
+
+	// Count limits the fault to the next Count requests. Zero keeps the
+	// fault active until ClearFaults is called.
+	Count int
+}
+
+// Server is a stateful in-memory fake of the Sevalla API
+type Server struct {
+	server *httptest.Server
+
+	// URL is the base URL of the fake API, suitable for sevalla.WithBaseURL
+	URL string
+
+	mu       sync.Mutex
+	apiKey   string
+	faults   map[string]*Fault
+	replays  map[string]*replay
+	requests []string
+	nextID   map[string]int
+
+	applications []*sevalla.Application
+	appLogs      map[string][]string
+	databases    []*sevalla.Database
+	backups      []*sevalla.Backup
+	staticSites  []*sevalla.StaticSite
+	deployments  []*sevalla.Deployment
+	pipelines    []*sevalla.Pipeline
+	runs         []*sevalla.PipelineRun
+
+	deployResult  sevalla.Status
+	deployMessage string
+}
+
+// NewServer starts a new fake Sevalla API server. It must be closed with
+// Close when no longer needed.
+func NewServer() *Server {
+	s := &Server{
+		faults:       make(map[string]*Fault),
+		replays:      make(map[string]*replay),
+		nextID:       make(map[string]int),
+		appLogs:      make(map[string][]string),
+		deployResult: sevalla.StatusSuccess,
+	}
+
+	mux := http.NewServeMux()
+	s.registerApplications(mux)
+	s.registerDatabases(mux)
+	s.registerStaticSites(mux)
+	s.registerDeployments(mux)
+	s.registerPipelines(mux)
+
+	s.server = httptest.NewServer(mux)
+	s.URL = s.server.URL
+
+	return s
+}
+
+// Close shuts down the server
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// Client returns a sevalla.Client configured to talk to the fake server.
+// Additional options are applied after the base URL.
+func (s *Server) Client(opts ...sevalla.ClientOption) *sevalla.Client {
+	opts = append([]sevalla.ClientOption{
+		sevalla.WithAPIKey("sevallatest"),
+		sevalla.WithBaseURL(s.URL),
+	}, opts...)
+
+	return sevalla.NewClient(opts...)
+}
+
+// SetAPIKey makes the server reject requests that do not authenticate with
+// the given key. An empty key accepts every request.
+func (s *Server) SetAPIKey(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.apiKey = key
+}
+
+// InjectFault makes the endpoint matching pattern fail or slow down. The
+// pattern is the method and path template of the endpoint, for example
+// "POST /applications" or "GET /deployments/{id}".
+func (s *Server) InjectFault(pattern string, fault Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.faults[pattern] = &fault
+}
+
+// ClearFaults removes every injected fault
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.faults = make(map[string]*Fault)
+}
+
+// SetDeploymentResult sets the final status of deployments and pipeline
+// runs started from now on, along with the error message of failures
+func (s *Server) SetDeploymentResult(status sevalla.Status, message string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.deployResult = status
+	s.deployMessage = message
+}
+
+// Requests returns the method and path of every request received so far
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]string(nil), s.requests...)
+}
+
+// handle registers a handler that records the request and applies faults
+func (s *Server) handle(mux *http.ServeMux, pattern string, h http.HandlerFunc) {
+	mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.requests = append(s.requests, r.Method+" "+r.URL.Path)
+		apiKey := s.apiKey
+
+		var fault Fault
+		if f, ok := s.faults[pattern]; ok {
+			fault = *f
+			if f.Count > 0 {
+				f.Count--
+				if f.Count == 0 {
+					delete(s.faults, pattern)
+				}
+			}
+		}
+		s.mu.Unlock()
+
+		if fault.Latency > 0 {
+			select {
+			case <-time.After(fault.Latency):
+			case <-r.Context().Done():
+				return
+			}
+		}
+
+		if apiKey != "" && r.Header.Get("Authorization") != "Bearer "+apiKey {
+			writeError(w, http.StatusUnauthorized, "unauthorized", "invalid API key")
+			return
+		}
+
+		if fault.StatusCode != 0 {
+			for k, v := range fault.Header {
+				w.Header()[k] = v
+			}
+			message := fault.Message
+			if message == "" {
+				message = http.StatusText(fault.StatusCode)
+			}
+			writeError(w, fault.StatusCode, fault.Code, message)
+			return
+		}
+
+		if key := r.Header.Get("Idempotency-Key"); key != "" && r.Method == http.MethodPost {
+			s.handleIdempotent(w, r, key, h)
+			return
+		}
+
+		if r.Method == http.MethodGet {
+			handleConditional(w, r, h)
+			return
+		}
+
+		h(w, r)
+	})
+}
+
+// handleConditional serves a GET request with an ETag derived from the
+// response body, answering 304 Not Modified when it matches If-None-Match
+func handleConditional(w http.ResponseWriter, r *http.Request, h http.HandlerFunc) {
+	rec := httptest.NewRecorder()
+	h(rec, r)
+
+	for k, v := range rec.Header() {
+		w.Header()[k] = v
+	}
+
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "application/json" {
+		w.WriteHeader(rec.Code)
+		_, _ = w.Write(rec.Body.Bytes())
+		return
+	}
+
+	etag := etagOf(rec.Body.Bytes())
+	w.Header().Set("ETag", etag)
+
+	if r.Header.Get("If-None-Match") == etag {
+		w.Header().Del("Content-Type")
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(rec.Body.Bytes())
+}
+
+// replay is the stored outcome of a request sent with an idempotency key
+type replay struct {
+	request string
+	done    bool
+	status  int
+	header  http.Header
+	body    []byte
+}
+
+// handleIdempotent serves a POST request carrying an idempotency key. The
+// first request with a key is processed and its response stored, later
+// requests with the same key get the stored response replayed.
+func (s *Server) handleIdempotent(w http.ResponseWriter, r *http.Request, key string, h http.HandlerFunc) {
+	request := r.Method + " " + r.URL.Path
+
+	s.mu.Lock()
+	stored, ok := s.replays[key]
+	if !ok {
+		s.replays[key] = &replay{request: request}
+	}
+	s.mu.Unlock()
+
+	switch {
+	case ok && stored.request != request:
+		writeError(w, http.StatusUnprocessableEntity, "idempotency_key_reused", "idempotency key was used for a different request")
+		return
+	case ok && !stored.done:
+		writeError(w, http.StatusConflict, "idempotency_key_in_use", "a request with this idempotency key is in progress")
+		return
+	case ok:
+		for k, v := range stored.header {
+			w.Header()[k] = v
+		}
+		w.Header().Set("Idempotent-Replayed", "true")
+		w.WriteHeader(stored.status)
+		_, _ = w.Write(stored.body)
+		return
+	}
+
+	rec := httptest.NewRecorder()
+	h(rec, r)
+
+	s.mu.Lock()
+	if rec.Code >= 500 {
+		// Server errors are not stored so the request can be retried
+		delete(s.replays, key)
+	} else {
+		s.replays[key] = &replay{request: request, done: true, status: rec.Code, header: rec.Header().Clone(), body: rec.Body.Bytes()}
+	}
+	s.mu.Unlock()
+
+	for k, v := range rec.Header() {
+		w.Header()[k] = v
+	}
+	w.WriteHeader(rec.Code)
+	_, _ = w.Write(rec.Body.Bytes())
+}
+
+// etagOf returns the ETag of a response body
+func etagOf(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:8]) + `"`
+}
+
+// preconditionFailed checks the If-Match and If-Unmodified-Since headers of
+// an update against the current resource, writing a 412 response when they
+// do not hold. The ETag of the resource is that of its GET response.
+func preconditionFailed(w http.ResponseWriter, r *http.Request, current interface{}, updatedAt time.Time) bool {
+	if ifMatch := r.Header.Get("If-Match"); ifMatch != "" && ifMatch != "*" {
+		var buf bytes.Buffer
+		_ = json.NewEncoder(&buf).Encode(current)
+		if ifMatch != etagOf(buf.Bytes()) {
+			writeError(w, http.StatusPreconditionFailed, "precondition_failed", "resource was modified")
+			return true
+		}
+	}
+
+	if since, err := http.ParseTime(r.Header.Get("If-Unmodified-Since")); err == nil && updatedAt.After(since) {
+		writeError(w, http.StatusPreconditionFailed, "precondition_failed", "resource was modified")
+		return true
+	}
+
+	return false
+}
+
+// newID returns the next identifier for the given prefix. Callers must
+// hold s.mu.
+func (s *Server) newID(prefix string) string {
+	s.nextID[prefix]++
+	return fmt.Sprintf("%s-%d", prefix, s.nextID[prefix])
+}
+
+// writeJSON writes v as a JSON response
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes an API error response
+func writeError(w http.ResponseWriter, status int, code, message string, details ...sevalla.ErrorDetail) {
+	writeJSON(w, status, &sevalla.ErrorResponse{
+		Message: message,
+		Code:    code,
+		Errors:  details,
+	})
+}
+
+// notFound writes a 404 response for the given resource kind
+func notFound(w http.ResponseWriter, kind string) {
+	writeError(w, http.StatusNotFound, "not_found", kind+" not found")
+}
+
+// decode reads the JSON request body into v, writing a 400 response when
+// the body is invalid
+func decode(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_body", "invalid request body: "+err.Error())
+		return false
+	}
+	return true
+}
+
+// writePage writes one page of items, honouring the page and per_page
+// query parameters and setting the Link header for the other pages
+func writePage[T any](w http.ResponseWriter, r *http.Request, items []T) {
+	q := r.URL.Query()
+	perPage, _ := strconv.Atoi(q.Get("per_page"))
+	if perPage <= 0 {
+		writeJSON(w, http.StatusOK, items)
+		return
+	}
+
+	page, _ := strconv.Atoi(q.Get("page"))
+	if page < 1 {
+		page = 1
+	}
+
+	last := (len(items) + perPage - 1) / perPage
+	if last < 1 {
+		last = 1
+	}
+
+	link := func(p int, rel string) string {
+		v := url.Values{}
+		for k, vals := range q {
+			v[k] = vals
+		}
+		v.Set("page", strconv.Itoa(p))
+		return fmt.Sprintf(`<%s?%s>; rel="%s"`, r.URL.Path, v.Encode(), rel)
+	}
+
+	links := link(1, "first") + ", " + link(last, "last")
+	if page < last {
+		links += ", " + link(page+1, "next")
+	}
+	if page > 1 {
+		links += ", " + link(page-1, "prev")
+	}
+	w.Header().Set("Link", links)
+
+	start := (page - 1) * perPage
+	if start > len(items) {
+		start = len(items)
+	}
+	end := start + perPage
+	if end > len(items) {
+		end = len(items)
+	}
+
+	writeJSON(w, http.StatusOK, items[start:end])
+}
+
+// find returns the first item matching the predicate
+func find[T any](items []*T, match func(*T) bool) *T {
+	for _, item := range items {
+		if match(item) {
+			return item
+		}
+	}
+	return nil
+}
+
+// wireAll converts items to their wire representation
+func wireAll[T, W any](items []*T, wire func(*T) W) []W {
+	result := make([]W, len(items))
+	for i, item := range items {
+		result[i] = wire(item)
+	}
+	return result
+}
+
+// filter returns the items matching the predicate
+func filter[T any](items []*T, match func(*T) bool) []*T {
+	result := make([]*T, 0, len(items))
+	for _, item := range items {
+		if match(item) {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// now returns the current time used for timestamps
+func now() time.Time {
+	return time.Now().UTC().Truncate(time.Second)
+}
+
+// secrets converts the plain values of a request to secrets
+func secrets(values map[string]string) map[string]sevalla.Secret {
+	if values == nil {
+		return nil
+	}
+	result := make(map[string]sevalla.Secret, len(values))
+	for key, value := range values {
+		result[key] = sevalla.Secret(value)
+	}
+	return result
+}
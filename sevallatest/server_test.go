@@ -0,0 +1,520 @@
+package sevallatest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/juststeveking/sevalla-go"
+)
+
+func TestServer_ApplicationLifecycle(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	client := srv.Client()
+	ctx := context.Background()
+
+	app, resp, err := client.Applications.Create(ctx, &sevalla.CreateApplicationRequest{
+		Name:          "web",
+		RepositoryURL: "https://github.com/user/web",
+	})
+	if err != nil {
+		t.Fatalf("Applications.Create returned error: %v", err)
+	}
+	if resp.StatusCode != http.StatusCreated {
+		t.Errorf("Expected status 201, got %d", resp.StatusCode)
+	}
+	if app.ID == "" || app.State != sevalla.StatePending {
+		t.Errorf("Unexpected application: %+v", app)
+	}
+
+	got, _, err := client.Applications.Get(ctx, app.ID)
+	if err != nil {
+		t.Fatalf("Applications.Get returned error: %v", err)
+	}
+	if got.Name != "web" {
+		t.Errorf("Expected name 'web', got %s", got.Name)
+	}
+
+	got, _, err = client.Applications.Update(ctx, app.ID, &sevalla.UpdateApplicationRequest{Branch: sevalla.String("develop")})
+	if err != nil {
+		t.Fatalf("Applications.Update returned error: %v", err)
+	}
+	if got.Branch != "develop" {
+		t.Errorf("Expected branch 'develop', got %s", got.Branch)
+	}
+
+	if _, _, err := client.Applications.Create(ctx, &sevalla.CreateApplicationRequest{Name: "web"}); !sevalla.IsConflict(err) {
+		t.Errorf("Expected conflict for duplicate name, got %v", err)
+	}
+
+	if _, err := client.Applications.Delete(ctx, app.ID); err != nil {
+		t.Fatalf("Applications.Delete returned error: %v", err)
+	}
+	if _, _, err := client.Applications.Get(ctx, app.ID); !sevalla.IsNotFound(err) {
+		t.Errorf("Expected not found after delete, got %v", err)
+	}
+}
+
+func TestServer_DeploymentProgresses(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	client := srv.Client()
+	ctx := context.Background()
+
+	app, _, err := client.Applications.Create(ctx, &sevalla.CreateApplicationRequest{Name: "web"})
+	if err != nil {
+		t.Fatalf("Applications.Create returned error: %v", err)
+	}
+
+	deployment, _, err := client.Applications.Deploy(ctx, app.ID)
+	if err != nil {
+		t.Fatalf("Applications.Deploy returned error: %v", err)
+	}
+
+	var states []sevalla.Status
+	final, err := client.Deployments.WaitForDeployment(ctx, deployment.ID, &sevalla.WaitOptions[*sevalla.Deployment]{
+		PollInterval: time.Millisecond,
+		OnTransition: func(d *sevalla.Deployment) { states = append(states, d.State) },
+	})
+	if err != nil {
+		t.Fatalf("WaitForDeployment returned error: %v", err)
+	}
+
+	want := []sevalla.Status{sevalla.StatusBuilding, sevalla.StatusDeploying, sevalla.StatusSuccess}
+	if !reflect.DeepEqual(states, want) {
+		t.Errorf("Expected states %v, got %v", want, states)
+	}
+	if final.CompletedAt == nil {
+		t.Error("Expected CompletedAt to be set")
+	}
+
+	app, _, err = client.Applications.Get(ctx, app.ID)
+	if err != nil {
+		t.Fatalf("Applications.Get returned error: %v", err)
+	}
+	if app.State != sevalla.StateRunning || app.LastDeploymentID != deployment.ID {
+		t.Errorf("Expected running application deployed by %s, got %+v", deployment.ID, app)
+	}
+}
+
+func TestServer_AddApplicationReturnsCopy(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	env := map[string]sevalla.Secret{"PORT": "8080"}
+	app := srv.AddApplication(sevalla.Application{Name: "web", EnvironmentVars: env})
+	app.Name = "changed"
+	app.EnvironmentVars["PORT"] = "9090"
+	env["DEBUG"] = "true"
+
+	got, _, err := srv.Client().Applications.Get(context.Background(), app.ID)
+	if err != nil {
+		t.Fatalf("Applications.Get returned error: %v", err)
+	}
+	if got.Name != "web" {
+		t.Errorf("Expected name web, got %q", got.Name)
+	}
+	if len(got.EnvironmentVars) != 1 || got.EnvironmentVars["PORT"].Reveal() != "8080" {
+		t.Errorf("Expected PORT=8080 only, got %v", sevalla.RevealSecrets(got.EnvironmentVars))
+	}
+}
+
+func TestServer_DeploymentFailure(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	srv.SetDeploymentResult(sevalla.StatusFailed, "build failed")
+	site := srv.AddStaticSite(sevalla.StaticSite{Name: "docs"})
+
+	client := srv.Client()
+	ctx := context.Background()
+
+	deployment, _, err := client.StaticSites.Deploy(ctx, site.ID)
+	if err != nil {
+		t.Fatalf("StaticSites.Deploy returned error: %v", err)
+	}
+
+	_, err = client.Deployments.WaitForDeployment(ctx, deployment.ID, &sevalla.WaitOptions[*sevalla.Deployment]{
+		PollInterval: time.Millisecond,
+	})
+
+	var deployErr *sevalla.DeploymentError
+	if !errors.As(err, &deployErr) {
+		t.Fatalf("Expected DeploymentError, got %v", err)
+	}
+	if deployErr.Deployment.ErrorMessage != "build failed" {
+		t.Errorf("Expected error message 'build failed', got %q", deployErr.Deployment.ErrorMessage)
+	}
+
+	got, _, err := client.StaticSites.Get(ctx, site.ID)
+	if err != nil {
+		t.Fatalf("StaticSites.Get returned error: %v", err)
+	}
+	if got.State != sevalla.StateFailed {
+		t.Errorf("Expected failed static site, got %s", got.State)
+	}
+}
+
+func TestServer_InjectFault(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	srv.InjectFault("GET /applications", Fault{
+		StatusCode: http.StatusServiceUnavailable,
+		Message:    "maintenance",
+		Count:      2,
+	})
+
+	policy := sevalla.DefaultRetryPolicy()
+	policy.BaseDelay = time.Millisecond
+
+	client := srv.Client(sevalla.WithRetryPolicy(policy))
+
+	if _, _, err := client.Applications.List(context.Background(), nil); err != nil {
+		t.Fatalf("Expected retries to recover from injected faults, got %v", err)
+	}
+
+	requests := srv.Requests()
+	if len(requests) != 3 {
+		t.Errorf("Expected 3 requests, got %v", requests)
+	}
+
+	srv.InjectFault("POST /databases", Fault{StatusCode: http.StatusForbidden, Code: "forbidden"})
+	_, _, err := client.Databases.Create(context.Background(), &sevalla.CreateDatabaseRequest{Name: "db", Type: sevalla.EngineRedis})
+	if !sevalla.IsForbidden(err) {
+		t.Errorf("Expected forbidden error, got %v", err)
+	}
+
+	srv.ClearFaults()
+	if _, _, err := client.Databases.Create(context.Background(), &sevalla.CreateDatabaseRequest{Name: "db", Type: sevalla.EngineRedis}); err != nil {
+		t.Errorf("Expected create to succeed after ClearFaults, got %v", err)
+	}
+}
+
+func TestServer_InjectLatency(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	srv.InjectFault("GET /pipelines", Fault{Latency: time.Second})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, _, err := srv.Client().Pipelines.List(ctx, nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestServer_Pagination(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	for i := 0; i < 5; i++ {
+		srv.AddApplication(sevalla.Application{})
+	}
+
+	apps, err := srv.Client().Applications.ListAll(context.Background(), &sevalla.ListOptions{PerPage: 2})
+	if err != nil {
+		t.Fatalf("Applications.ListAll returned error: %v", err)
+	}
+	if len(apps) != 5 {
+		t.Errorf("Expected 5 applications, got %d", len(apps))
+	}
+	if len(srv.Requests()) != 3 {
+		t.Errorf("Expected 3 page requests, got %v", srv.Requests())
+	}
+}
+
+func TestServer_DatabaseBackups(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	client := srv.Client()
+	ctx := context.Background()
+
+	db, _, err := client.Databases.Create(ctx, &sevalla.CreateDatabaseRequest{Name: "main", Type: sevalla.EnginePostgreSQL})
+	if err != nil {
+		t.Fatalf("Databases.Create returned error: %v", err)
+	}
+	if db.Version != "16" || db.InternalURL == "" {
+		t.Errorf("Expected defaults to be filled in, got %+v", db)
+	}
+
+	backup, _, err := client.Databases.CreateBackup(ctx, db.ID, &sevalla.CreateBackupRequest{})
+	if err != nil {
+		t.Fatalf("Databases.CreateBackup returned error: %v", err)
+	}
+
+	backup, err = client.Databases.WaitForBackup(ctx, db.ID, backup.ID, &sevalla.WaitOptions[*sevalla.Backup]{
+		PollInterval: time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("WaitForBackup returned error: %v", err)
+	}
+	if backup.URL == "" {
+		t.Error("Expected completed backup to have a download URL")
+	}
+
+	if _, err := client.Databases.RestoreFromBackup(ctx, db.ID, &sevalla.RestoreBackupRequest{BackupID: backup.ID}); err != nil {
+		t.Errorf("RestoreFromBackup returned error: %v", err)
+	}
+
+	_, _, err = client.Databases.Create(ctx, &sevalla.CreateDatabaseRequest{Type: "oracle"})
+	var errResp *sevalla.ErrorResponse
+	if !errors.As(err, &errResp) || len(errResp.Errors) != 2 {
+		t.Errorf("Expected validation error with 2 details, got %v", err)
+	}
+}
+
+func TestServer_PipelineRuns(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	client := srv.Client()
+	ctx := context.Background()
+
+	pipeline, _, err := client.Pipelines.Create(ctx, &sevalla.CreatePipelineRequest{
+		Name:  "ci",
+		Steps: []sevalla.PipelineStep{{Name: "test", Command: "go test ./..."}},
+	})
+	if err != nil {
+		t.Fatalf("Pipelines.Create returned error: %v", err)
+	}
+
+	run, _, err := client.Pipelines.Run(ctx, pipeline.ID)
+	if err != nil {
+		t.Fatalf("Pipelines.Run returned error: %v", err)
+	}
+
+	run, err = client.Pipelines.WaitForRun(ctx, pipeline.ID, run.ID, &sevalla.WaitOptions[*sevalla.PipelineRun]{
+		PollInterval: time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("WaitForRun returned error: %v", err)
+	}
+	if run.State != sevalla.StatusSuccess {
+		t.Errorf("Expected successful run, got %s", run.State)
+	}
+
+	runs, err := client.Pipelines.ListAllRuns(ctx, pipeline.ID, nil)
+	if err != nil {
+		t.Fatalf("Pipelines.ListAllRuns returned error: %v", err)
+	}
+	if len(runs) != 1 {
+		t.Errorf("Expected 1 run, got %d", len(runs))
+	}
+}
+
+func TestServer_TailLogs(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	app := srv.AddApplication(sevalla.Application{Name: "web"})
+	srv.WriteLogs(app.ID, "starting", "listening on :8080")
+
+	stream, err := srv.Client().Applications.TailLogs(context.Background(), app.ID, nil)
+	if err != nil {
+		t.Fatalf("TailLogs returned error: %v", err)
+	}
+	defer stream.Close()
+
+	var messages []string
+	for line := range stream.Lines() {
+		messages = append(messages, line.Message)
+	}
+
+	if !reflect.DeepEqual(messages, []string{"starting", "listening on :8080"}) {
+		t.Errorf("Unexpected log lines: %v", messages)
+	}
+}
+
+func TestServer_SetAPIKey(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	srv.SetAPIKey("secret")
+
+	if _, _, err := srv.Client().Applications.List(context.Background(), nil); !sevalla.IsUnauthorized(err) {
+		t.Errorf("Expected unauthorized error, got %v", err)
+	}
+
+	if _, _, err := srv.Client(sevalla.WithAPIKey("secret")).Applications.List(context.Background(), nil); err != nil {
+		t.Errorf("Expected request with valid key to succeed, got %v", err)
+	}
+}
+
+func TestServer_IdempotencyKeyReplays(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	client := srv.Client()
+	ctx := sevalla.ContextWithIdempotencyKey(context.Background(), "create-web")
+
+	first, resp, err := client.Applications.Create(ctx, &sevalla.CreateApplicationRequest{Name: "web"})
+	if err != nil {
+		t.Fatalf("Applications.Create returned error: %v", err)
+	}
+	if resp.IdempotentReplayed {
+		t.Error("Expected the first response not to be replayed")
+	}
+
+	second, resp, err := client.Applications.Create(ctx, &sevalla.CreateApplicationRequest{Name: "web"})
+	if err != nil {
+		t.Fatalf("Applications.Create returned error: %v", err)
+	}
+	if !resp.IdempotentReplayed || second.ID != first.ID {
+		t.Errorf("Expected replay of %s, got %s (replayed %v)", first.ID, second.ID, resp.IdempotentReplayed)
+	}
+
+	apps, _, _ := client.Applications.List(context.Background(), nil)
+	if len(apps) != 1 {
+		t.Errorf("Expected 1 application, got %d", len(apps))
+	}
+
+	_, _, err = client.Databases.Create(ctx, &sevalla.CreateDatabaseRequest{Name: "db", Type: sevalla.EngineRedis})
+	if !sevalla.IsIdempotencyError(err) || !sevalla.IsUnprocessableEntity(err) {
+		t.Errorf("Expected *IdempotencyError for a reused key, got %v", err)
+	}
+}
+
+func TestServer_ValidationErrors(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	client := srv.Client()
+
+	_, _, err := client.Databases.Create(context.Background(), &sevalla.CreateDatabaseRequest{Type: "oracle"})
+
+	var verr *sevalla.ValidationErrors
+	if !errors.As(err, &verr) {
+		t.Fatalf("Expected *sevalla.ValidationErrors, got %v", err)
+	}
+
+	if len(verr.Field("Name")) != 1 || len(verr.Field("Type")) != 1 {
+		t.Errorf("Expected errors for Name and Type, got %v", verr.ByField())
+	}
+}
+
+func TestServer_ConditionalGet(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	client := srv.Client(sevalla.WithCache(nil))
+	ctx := context.Background()
+
+	app, _, err := client.Applications.Create(ctx, &sevalla.CreateApplicationRequest{Name: "web"})
+	if err != nil {
+		t.Fatalf("Applications.Create returned error: %v", err)
+	}
+
+	if _, resp, err := client.Applications.Get(ctx, app.ID); err != nil || resp.CacheHit {
+		t.Fatalf("Expected an uncached first Get, got hit=%v err=%v", resp.CacheHit, err)
+	}
+
+	got, resp, err := client.Applications.Get(ctx, app.ID)
+	if err != nil {
+		t.Fatalf("Applications.Get returned error: %v", err)
+	}
+	if !resp.CacheHit || got.Name != "web" {
+		t.Errorf("Expected a cache hit for web, got hit=%v name=%s", resp.CacheHit, got.Name)
+	}
+
+	name := "api"
+	if _, _, err := client.Applications.Update(ctx, app.ID, &sevalla.UpdateApplicationRequest{Name: &name}); err != nil {
+		t.Fatalf("Applications.Update returned error: %v", err)
+	}
+
+	got, resp, err = client.Applications.Get(ctx, app.ID)
+	if err != nil {
+		t.Fatalf("Applications.Get returned error: %v", err)
+	}
+	if resp.CacheHit || got.Name != "api" {
+		t.Errorf("Expected the updated application, got hit=%v name=%s", resp.CacheHit, got.Name)
+	}
+}
+
+func TestServer_ConditionalUpdate(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	client := srv.Client()
+	ctx := context.Background()
+
+	p, _, err := client.Pipelines.Create(ctx, &sevalla.CreatePipelineRequest{Name: "ci", Steps: []sevalla.PipelineStep{{Name: "test", Command: "go test ./..."}}})
+	if err != nil {
+		t.Fatalf("Pipelines.Create returned error: %v", err)
+	}
+
+	_, resp, err := client.Pipelines.Get(ctx, p.ID)
+	if err != nil || resp.ETag == "" {
+		t.Fatalf("Expected an ETag, got %q (err %v)", resp.ETag, err)
+	}
+	stale := sevalla.WithRequestOptions(ctx, sevalla.RequestIfMatch(resp.ETag))
+
+	if _, _, err := client.Pipelines.Update(ctx, p.ID, &sevalla.UpdatePipelineRequest{Branch: sevalla.String("develop")}); err != nil {
+		t.Fatalf("Pipelines.Update returned error: %v", err)
+	}
+
+	if _, _, err := client.Pipelines.Update(stale, p.ID, &sevalla.UpdatePipelineRequest{Name: sevalla.String("stale")}); !sevalla.IsPreconditionFailed(err) {
+		t.Fatalf("Expected a precondition failure for a stale ETag, got %v", err)
+	}
+
+	updated, _, err := client.Pipelines.Modify(ctx, p.ID, func(p *sevalla.Pipeline) (*sevalla.UpdatePipelineRequest, error) {
+		return &sevalla.UpdatePipelineRequest{Name: sevalla.String(p.Name + "-" + p.Branch)}, nil
+	})
+	if err != nil {
+		t.Fatalf("Pipelines.Modify returned error: %v", err)
+	}
+	if updated.Name != "ci-develop" {
+		t.Errorf("Expected name ci-develop, got %s", updated.Name)
+	}
+}
+
+func TestServer_RevealsSecrets(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	client := srv.Client()
+	ctx := context.Background()
+
+	app, _, err := client.Applications.Create(ctx, &sevalla.CreateApplicationRequest{
+		Name:            "web",
+		EnvironmentVars: map[string]string{"API_KEY": "secret"},
+	})
+	if err != nil {
+		t.Fatalf("Applications.Create returned error: %v", err)
+	}
+	if app.EnvironmentVars["API_KEY"].Reveal() != "secret" {
+		t.Errorf("Expected API_KEY secret, got %q", app.EnvironmentVars["API_KEY"].Reveal())
+	}
+
+	if _, err := client.Applications.SetEnvironmentVariables(ctx, app.ID, map[string]string{"API_KEY": "rotated"}); err != nil {
+		t.Fatalf("Applications.SetEnvironmentVariables returned error: %v", err)
+	}
+	vars, _, err := client.Applications.GetEnvironmentVariables(ctx, app.ID)
+	if err != nil {
+		t.Fatalf("Applications.GetEnvironmentVariables returned error: %v", err)
+	}
+	if vars["API_KEY"].Reveal() != "rotated" {
+		t.Errorf("Expected API_KEY rotated, got %q", vars["API_KEY"].Reveal())
+	}
+
+	db, _, err := client.Databases.Create(ctx, &sevalla.CreateDatabaseRequest{Name: "main", Type: sevalla.EnginePostgreSQL})
+	if err != nil {
+		t.Fatalf("Databases.Create returned error: %v", err)
+	}
+	creds, _, err := client.Databases.GetCredentials(ctx, db.ID)
+	if err != nil {
+		t.Fatalf("Databases.GetCredentials returned error: %v", err)
+	}
+	if creds.Username.Reveal() != "sevalla" || creds.Password.Reveal() != "secret-"+db.ID {
+		t.Errorf("Expected revealed credentials, got %s/%s", creds.Username.Reveal(), creds.Password.Reveal())
+	}
+}
@@ -0,0 +1,145 @@
+package sevallatest
+
+import (
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+
+	"github.com/juststeveking/sevalla-go"
+)
+
+// AddStaticSite seeds the server with a static site. An ID is assigned when
+// the site has none. The server keeps its own copy, so changes to the
+// returned site do not affect the server.
+func (s *Server) AddStaticSite(site sevalla.StaticSite) *sevalla.StaticSite {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if site.ID == "" {
+		site.ID = s.newID("site")
+	}
+	if site.CreatedAt.IsZero() {
+		site.CreatedAt = now()
+		site.UpdatedAt = site.CreatedAt
+	}
+	stored := site
+	stored.EnvironmentVars = maps.Clone(site.EnvironmentVars)
+	stored.CustomDomains = slices.Clone(site.CustomDomains)
+	s.staticSites = append(s.staticSites, &stored)
+
+	return &site
+}
+
+// staticSite returns the static site with the given ID. Callers must hold
+// s.mu.
+func (s *Server) staticSite(id string) *sevalla.StaticSite {
+	return find(s.staticSites, func(site *sevalla.StaticSite) bool { return site.ID == id })
+}
+
+// staticSiteJSON is the API representation of a static site, which carries
+// the environment variable values in the clear
+type staticSiteJSON struct {
+	*sevalla.StaticSite
+	EnvironmentVars map[string]string `json:"environment_variables,omitempty"`
+}
+
+// staticSiteWire returns the API representation of a static site
+func staticSiteWire(site *sevalla.StaticSite) staticSiteJSON {
+	return staticSiteJSON{StaticSite: site, EnvironmentVars: sevalla.RevealSecrets(site.EnvironmentVars)}
+}
+
+func (s *Server) registerStaticSites(mux *http.ServeMux) {
+	s.handle(mux, "GET /static-sites", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		writePage(w, r, wireAll(s.staticSites, staticSiteWire))
+	})
+
+	s.handle(mux, "POST /static-sites", func(w http.ResponseWriter, r *http.Request) {
+		var req sevalla.CreateStaticSiteRequest
+		if !decode(w, r, &req) {
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		if req.Name == "" {
+			writeError(w, http.StatusUnprocessableEntity, "validation_failed", "validation failed",
+				sevalla.ErrorDetail{Field: "name", Code: "required", Message: "name is required"})
+			return
+		}
+
+		site := &sevalla.StaticSite{
+			ID:              s.newID("site"),
+			Name:            req.Name,
+			State:           sevalla.StatePending,
+			RepositoryURL:   req.RepositoryURL,
+			Branch:          req.Branch,
+			Region:          req.Region,
+			BuildCommand:    req.BuildCommand,
+			OutputDirectory: req.OutputDirectory,
+			EnvironmentVars: secrets(req.EnvironmentVars),
+			AutoDeploy:      req.AutoDeploy,
+			CDNEnabled:      req.CDNEnabled,
+			SSLEnabled:      req.SSLEnabled,
+			CreatedAt:       now(),
+		}
+		site.UpdatedAt = site.CreatedAt
+		site.URL = fmt.Sprintf("https://%s.sevalla.page", site.Name)
+		if site.Branch == "" {
+			site.Branch = "main"
+		}
+		s.staticSites = append(s.staticSites, site)
+
+		writeJSON(w, http.StatusCreated, staticSiteWire(site))
+	})
+
+	s.handle(mux, "GET /static-sites/{id}", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		site := s.staticSite(r.PathValue("id"))
+		if site == nil {
+			notFound(w, "static site")
+			return
+		}
+
+		writeJSON(w, http.StatusOK, staticSiteWire(site))
+	})
+
+	s.handle(mux, "DELETE /static-sites/{id}", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		id := r.PathValue("id")
+		if s.staticSite(id) == nil {
+			notFound(w, "static site")
+			return
+		}
+
+		s.staticSites = filter(s.staticSites, func(site *sevalla.StaticSite) bool { return site.ID != id })
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	s.handle(mux, "POST /static-sites/{id}/deployments", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		site := s.staticSite(r.PathValue("id"))
+		if site == nil {
+			notFound(w, "static site")
+			return
+		}
+
+		d := s.newDeployment()
+		d.StaticSiteID = site.ID
+		d.Branch = site.Branch
+		site.State = sevalla.StateDeploying
+		site.LastDeploymentID = d.ID
+
+		writeJSON(w, http.StatusCreated, d)
+	})
+}
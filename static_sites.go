@@ -3,6 +3,7 @@ package sevalla
 import (
 	"context"
 	"fmt"
+	"iter"
 )
 
 // StaticSitesService handles communication with the static site-related
@@ -25,8 +26,24 @@ type CreateStaticSiteRequest struct {
 	SSLEnabled      bool              `json:"ssl_enabled,omitempty"`
 }
 
+// Validate checks the request for errors the API would reject
+func (r *CreateStaticSiteRequest) Validate() error {
+	if r == nil {
+		return nilRequest()
+	}
+
+	var v validator
+	v.required("Name", "name", r.Name)
+	v.repositoryURL("RepositoryURL", "repository_url", r.RepositoryURL)
+	v.region("Region", "location", r.Region)
+	return v.err()
+}
+
 // List returns all static sites
 func (s *StaticSitesService) List(ctx context.Context, opts *ListOptions) ([]*StaticSite, *Response, error) {
+	ctx, span := s.client.startSpan(ctx, "StaticSites.List")
+	defer span.End()
+
 	u := "static-sites"
 	req, err := s.client.NewRequestWithQuery(ctx, "GET", u, opts)
 	if err != nil {
@@ -42,8 +59,21 @@ func (s *StaticSitesService) List(ctx context.Context, opts *ListOptions) ([]*St
 	return sites, resp, nil
 }
 
+// ListIter returns an iterator over all static sites across all pages
+func (s *StaticSitesService) ListIter(ctx context.Context, opts *ListOptions) iter.Seq2[*StaticSite, error] {
+	return paginate(ctx, s.client, "StaticSites.ListIter", opts, s.List)
+}
+
+// ListAll returns all static sites across all pages
+func (s *StaticSitesService) ListAll(ctx context.Context, opts *ListOptions) ([]*StaticSite, error) {
+	return collect(paginate(ctx, s.client, "StaticSites.ListAll", opts, s.List))
+}
+
 // Get returns a single static site by ID
 func (s *StaticSitesService) Get(ctx context.Context, id string) (*StaticSite, *Response, error) {
+	ctx, span := s.client.startSpan(ctx, "StaticSites.Get", Attribute{Key: attrStaticSiteID, Value: id})
+	defer span.End()
+
 	u := fmt.Sprintf("static-sites/%s", id)
 	req, err := s.client.NewRequest(ctx, "GET", u, nil)
 	if err != nil {
@@ -61,6 +91,9 @@ func (s *StaticSitesService) Get(ctx context.Context, id string) (*StaticSite, *
 
 // Create creates a new static site
 func (s *StaticSitesService) Create(ctx context.Context, createReq *CreateStaticSiteRequest) (*StaticSite, *Response, error) {
+	ctx, span := s.client.startSpan(ctx, "StaticSites.Create")
+	defer span.End()
+
 	u := "static-sites"
 	req, err := s.client.NewRequest(ctx, "POST", u, createReq)
 	if err != nil {
@@ -78,6 +111,9 @@ func (s *StaticSitesService) Create(ctx context.Context, createReq *CreateStatic
 
 // Delete deletes a static site
 func (s *StaticSitesService) Delete(ctx context.Context, id string) (*Response, error) {
+	ctx, span := s.client.startSpan(ctx, "StaticSites.Delete", Attribute{Key: attrStaticSiteID, Value: id})
+	defer span.End()
+
 	u := fmt.Sprintf("static-sites/%s", id)
 	req, err := s.client.NewRequest(ctx, "DELETE", u, nil)
 	if err != nil {
@@ -89,6 +125,9 @@ func (s *StaticSitesService) Delete(ctx context.Context, id string) (*Response,
 
 // Deploy triggers a new deployment for a static site
 func (s *StaticSitesService) Deploy(ctx context.Context, id string) (*Deployment, *Response, error) {
+	ctx, span := s.client.startSpan(ctx, "StaticSites.Deploy", Attribute{Key: attrStaticSiteID, Value: id})
+	defer span.End()
+
 	u := fmt.Sprintf("static-sites/%s/deployments", id)
 	req, err := s.client.NewRequest(ctx, "POST", u, nil)
 	if err != nil {
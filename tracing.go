@@ -0,0 +1,116 @@
+package sevalla
+
+import (
+	"context"
+	"net/http"
+)
+
+// Span attribute keys set by the client
+const (
+	attrApplicationID = "sevalla.application.id"
+	attrDatabaseID    = "sevalla.database.id"
+	attrBackupID      = "sevalla.backup.id"
+	attrDeploymentID  = "sevalla.deployment.id"
+	attrStaticSiteID  = "sevalla.static_site.id"
+	attrPipelineID    = "sevalla.pipeline.id"
+	attrPipelineRunID = "sevalla.pipeline_run.id"
+	attrStatusCode    = "http.response.status_code"
+	attrErrorCode     = "sevalla.error.code"
+	attrRequestID     = "sevalla.request_id"
+	attrRetryCount    = "sevalla.retry_count"
+)
+
+// Tracer starts spans around service operations. The otelsevalla module
+// provides an implementation backed by OpenTelemetry.
+type Tracer interface {
+	// Start starts a span with the given name, e.g.
+	// "sevalla.Applications.Deploy", as a child of the span in ctx
+	Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span)
+
+	// Inject writes the trace context of ctx into the request headers so
+	// that it is propagated to the API
+	Inject(ctx context.Context, header http.Header)
+}
+
+// Span is a single traced service operation
+type Span interface {
+	SetAttributes(attrs ...Attribute)
+	RecordError(err error)
+	End()
+}
+
+// Attribute is a key-value pair attached to a span. Values are strings or
+// ints.
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+// WithTracer opens a span for every service operation and propagates the
+// trace context to the API. Spans carry the IDs of the resources involved,
+// and the HTTP status, error code, request ID and retry count of the
+// underlying requests.
+func WithTracer(tracer Tracer) ClientOption {
+	return func(c *Client) {
+		c.tracer = tracer
+	}
+}
+
+// spanKey is the context key holding the span of the current operation
+type spanKey struct{}
+
+// noopSpan is used when tracing is disabled
+type noopSpan struct{}
+
+func (noopSpan) SetAttributes(...Attribute) {}
+func (noopSpan) RecordError(error)          {}
+func (noopSpan) End()                       {}
+
+// startSpan records the current service operation in the context and
+// starts a span for it when a tracer is set
+func (c *Client) startSpan(ctx context.Context, operation string, attrs ...Attribute) (context.Context, Span) {
+	ctx = context.WithValue(ctx, operationKey{}, operation)
+	if c.tracer == nil {
+		return ctx, noopSpan{}
+	}
+
+	ctx, span := c.tracer.Start(ctx, "sevalla."+operation, attrs...)
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+// spanFromContext returns the span of the current operation
+func spanFromContext(ctx context.Context) Span {
+	if span, ok := ctx.Value(spanKey{}).(Span); ok {
+		return span
+	}
+	return noopSpan{}
+}
+
+// tracingMiddleware propagates the trace context and records the outcome of
+// a request on the span of the operation that sent it
+func tracingMiddleware(tracer Tracer) Middleware {
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request, v interface{}) (*Response, error) {
+			tracer.Inject(req.Context(), req.Header)
+
+			response, err := next.Do(req, v)
+
+			span := spanFromContext(req.Context())
+			if response != nil {
+				span.SetAttributes(Attribute{Key: attrStatusCode, Value: response.StatusCode})
+				if id := requestID(response, err); id != "" {
+					span.SetAttributes(Attribute{Key: attrRequestID, Value: id})
+				}
+			}
+
+			if err != nil {
+				if e, ok := asErrorResponse(err); ok && e.Code != "" {
+					span.SetAttributes(Attribute{Key: attrErrorCode, Value: e.Code})
+				}
+				span.RecordError(err)
+			}
+
+			return response, err
+		})
+	}
+}
@@ -1,6 +1,9 @@
 package sevalla
 
-import "time"
+import (
+	"slices"
+	"time"
+)
 
 // Region represents a deployment region
 type Region string
@@ -13,6 +16,15 @@ const (
 	RegionAsiaSouth  Region = "asia-south1"
 )
 
+// IsValid returns true if the region is one of the available regions
+func (r Region) IsValid() bool {
+	switch r {
+	case RegionUSCentral, RegionUSEast, RegionEuropeWest, RegionAsiaSouth:
+		return true
+	}
+	return false
+}
+
 // Plan represents a pricing/compute plan tier
 type Plan string
 
@@ -25,6 +37,15 @@ const (
 	PlanEnterprise Plan = "enterprise"
 )
 
+// IsValid returns true if the plan is one of the available plan tiers
+func (p Plan) IsValid() bool {
+	switch p {
+	case PlanHobby, PlanStarter, PlanPro, PlanBusiness, PlanEnterprise:
+		return true
+	}
+	return false
+}
+
 // ApplicationState represents the state of an application
 type ApplicationState string
 
@@ -49,6 +70,34 @@ const (
 	EngineRedis      Engine = "redis"
 )
 
+// IsValid returns true if the engine is one of the available engines
+func (e Engine) IsValid() bool {
+	switch e {
+	case EnginePostgreSQL, EngineMySQL, EngineMongoDB, EngineRedis:
+		return true
+	}
+	return false
+}
+
+// engineVersions are the versions available for each engine, oldest first
+var engineVersions = map[Engine][]string{
+	EnginePostgreSQL: {"12", "13", "14", "15", "16", "17"},
+	EngineMySQL:      {"5.7", "8.0", "8.4"},
+	EngineMongoDB:    {"5.0", "6.0", "7.0"},
+	EngineRedis:      {"6.2", "7.0", "7.2"},
+}
+
+// Versions returns the versions available for the engine, oldest first
+func (e Engine) Versions() []string {
+	return slices.Clone(engineVersions[e])
+}
+
+// SupportsVersion returns true if the engine is available in the given
+// version
+func (e Engine) SupportsVersion(version string) bool {
+	return slices.Contains(engineVersions[e], version)
+}
+
 // Status represents a deployment status
 type Status string
 
@@ -62,6 +111,11 @@ const (
 	StatusCancelled Status = "cancelled"
 )
 
+// IsTerminal returns true if the status will not change anymore
+func (s Status) IsTerminal() bool {
+	return s == StatusSuccess || s == StatusFailed || s == StatusCancelled
+}
+
 // Application represents a Sevalla application
 type Application struct {
 	ID               string                 `json:"id"`
@@ -72,7 +126,7 @@ type Application struct {
 	Region           Region                 `json:"location"`
 	Plan             Plan                   `json:"pod_size"`
 	Replicas         int                    `json:"replicas"`
-	EnvironmentVars  map[string]string      `json:"environment_variables,omitempty"`
+	EnvironmentVars  map[string]Secret      `json:"environment_variables,omitempty"`
 	BuildCommand     string                 `json:"build_command,omitempty"`
 	StartCommand     string                 `json:"start_command,omitempty"`
 	Port             int                    `json:"port,omitempty"`
@@ -99,8 +153,8 @@ type Database struct {
 	Backups     bool                   `json:"backups_enabled"`
 	PublicURL   string                 `json:"public_url,omitempty"`
 	InternalURL string                 `json:"internal_url,omitempty"`
-	Username    string                 `json:"username,omitempty"`
-	Password    string                 `json:"password,omitempty"`
+	Username    Secret                 `json:"username,omitempty"`
+	Password    Secret                 `json:"password,omitempty"`
 	SSLEnabled  bool                   `json:"ssl_enabled"`
 	CreatedAt   time.Time              `json:"created_at"`
 	UpdatedAt   time.Time              `json:"updated_at"`
@@ -117,7 +171,7 @@ type StaticSite struct {
 	Region           Region            `json:"location"`
 	BuildCommand     string            `json:"build_command,omitempty"`
 	OutputDirectory  string            `json:"output_directory,omitempty"`
-	EnvironmentVars  map[string]string `json:"environment_variables,omitempty"`
+	EnvironmentVars  map[string]Secret `json:"environment_variables,omitempty"`
 	URL              string            `json:"url,omitempty"`
 	CustomDomains    []string          `json:"custom_domains,omitempty"`
 	AutoDeploy       bool              `json:"auto_deploy"`
@@ -153,7 +207,7 @@ type Pipeline struct {
 	Trigger     string                 `json:"trigger"`
 	Branch      string                 `json:"branch"`
 	Steps       []PipelineStep         `json:"steps"`
-	Environment map[string]string      `json:"environment,omitempty"`
+	Environment map[string]Secret      `json:"environment,omitempty"`
 	CreatedAt   time.Time              `json:"created_at"`
 	UpdatedAt   time.Time              `json:"updated_at"`
 	Metadata    map[string]interface{} `json:"metadata,omitempty"`
@@ -199,8 +253,20 @@ type ListOptions struct {
 	Sort      string `url:"sort,omitempty"`
 	Order     string `url:"order,omitempty"`
 	CompanyID string `url:"company_id,omitempty"`
+
+	// MaxItems caps the total number of items returned by the ListAll
+	// helpers and List iterators. It is not sent to the API.
+	MaxItems int `url:"-"`
 }
 
+// Backup statuses
+const (
+	BackupStatusPending    = "pending"
+	BackupStatusInProgress = "in_progress"
+	BackupStatusCompleted  = "completed"
+	BackupStatusFailed     = "failed"
+)
+
 // Backup represents a database backup
 type Backup struct {
 	ID         string    `json:"id"`
@@ -0,0 +1,288 @@
+package sevalla
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// ValidationErrors is returned for 422 Unprocessable Entity responses, and by
+// the Validate methods of request types. It holds one ValidationError per
+// invalid field, with API field names mapped back to the fields of the
+// request struct.
+//
+//	var verr *sevalla.ValidationErrors
+//	if errors.As(err, &verr) {
+//		for _, fe := range verr.Fields {
+//			form.SetError(fe.Field, fe.Message)
+//		}
+//	}
+type ValidationErrors struct {
+	// ErrorResponse is the 422 response, nil for errors found before
+	// sending
+	ErrorResponse *ErrorResponse
+
+	Fields []*ValidationError
+}
+
+// Error returns the validation errors message
+func (e *ValidationErrors) Error() string {
+	if len(e.Fields) == 0 && e.ErrorResponse != nil {
+		return e.ErrorResponse.Error()
+	}
+
+	msgs := make([]string, 0, len(e.Fields))
+	for _, fe := range e.Fields {
+		if fe.Field == "" {
+			msgs = append(msgs, fe.Message)
+			continue
+		}
+		msgs = append(msgs, fe.Field+": "+fe.Message)
+	}
+
+	if e.ErrorResponse == nil || e.ErrorResponse.Response == nil {
+		return "sevalla: validation failed - " + strings.Join(msgs, "; ")
+	}
+
+	return fmt.Sprintf("sevalla: validation failed (%d) - %s", e.ErrorResponse.Response.StatusCode, strings.Join(msgs, "; "))
+}
+
+// Is reports whether target is ErrValidation, or a sentinel error matching
+// the underlying ErrorResponse
+func (e *ValidationErrors) Is(target error) bool {
+	return target == ErrValidation || e.ErrorResponse.Is(target)
+}
+
+// Temporary reports false, invalid requests never succeed when retried
+func (e *ValidationErrors) Temporary() bool {
+	return false
+}
+
+// Unwrap returns the underlying ErrorResponse, if any, followed by the
+// field errors
+func (e *ValidationErrors) Unwrap() []error {
+	errs := make([]error, 0, len(e.Fields)+1)
+	if e.ErrorResponse != nil {
+		errs = append(errs, e.ErrorResponse)
+	}
+	for _, fe := range e.Fields {
+		errs = append(errs, fe)
+	}
+	return errs
+}
+
+// Field returns the errors for the given Go field path, e.g. "Name"
+func (e *ValidationErrors) Field(name string) []*ValidationError {
+	var errs []*ValidationError
+	for _, fe := range e.Fields {
+		if fe.Field == name {
+			errs = append(errs, fe)
+		}
+	}
+	return errs
+}
+
+// ByField groups the errors by Go field path
+func (e *ValidationErrors) ByField() map[string][]*ValidationError {
+	fields := make(map[string][]*ValidationError, len(e.Fields))
+	for _, fe := range e.Fields {
+		fields[fe.Field] = append(fields[fe.Field], fe)
+	}
+	return fields
+}
+
+// newValidationErrors converts the error details of a 422 response into
+// field errors, using the type of the request body to name Go fields
+func newValidationErrors(e *ErrorResponse) *ValidationErrors {
+	var bodyType reflect.Type
+	if e.Response.Request != nil {
+		bodyType, _ = e.Response.Request.Context().Value(bodyTypeKey{}).(reflect.Type)
+	}
+
+	verr := &ValidationErrors{ErrorResponse: e}
+	for _, detail := range e.Errors {
+		verr.Fields = append(verr.Fields, &ValidationError{
+			Field:    goFieldPath(bodyType, detail.Field),
+			APIField: detail.Field,
+			Code:     detail.Code,
+			Message:  detail.Message,
+		})
+	}
+
+	return verr
+}
+
+// bodyTypeKey is the request context key holding the type of the body
+type bodyTypeKey struct{}
+
+// withBodyType records the type of the request body in the request context
+// so that validation errors can be mapped back to its fields
+func withBodyType(req *http.Request, body interface{}) *http.Request {
+	if body == nil {
+		return req
+	}
+	return req.WithContext(context.WithValue(req.Context(), bodyTypeKey{}, reflect.TypeOf(body)))
+}
+
+// jsonFields caches the JSON name to Go field name mapping of struct types
+var jsonFields sync.Map // map[reflect.Type]map[string]reflect.StructField
+
+// goFieldPath maps a dotted API field path, e.g. "environment_variables.KEY",
+// to the Go field path of t, e.g. "EnvironmentVars.KEY". Segments that do
+// not name a struct field are kept as they are.
+func goFieldPath(t reflect.Type, apiPath string) string {
+	if apiPath == "" {
+		return ""
+	}
+
+	segments := strings.Split(apiPath, ".")
+	for i, segment := range segments {
+		for t != nil && (t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice || t.Kind() == reflect.Map) {
+			t = t.Elem()
+		}
+		if t == nil || t.Kind() != reflect.Struct {
+			t = nil
+			continue
+		}
+
+		field, ok := structJSONFields(t)[segment]
+		if !ok {
+			t = nil
+			continue
+		}
+
+		segments[i] = field.Name
+		t = field.Type
+	}
+
+	return strings.Join(segments, ".")
+}
+
+// structJSONFields returns the fields of a struct type keyed by JSON name
+func structJSONFields(t reflect.Type) map[string]reflect.StructField {
+	if fields, ok := jsonFields.Load(t); ok {
+		return fields.(map[string]reflect.StructField)
+	}
+
+	fields := make(map[string]reflect.StructField, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		switch name {
+		case "-":
+			continue
+		case "":
+			name = field.Name
+		}
+		fields[name] = field
+	}
+
+	jsonFields.Store(t, fields)
+	return fields
+}
+
+// Validator is implemented by request types that can be checked before
+// they are sent
+type Validator interface {
+	Validate() error
+}
+
+// WithValidation makes the client call Validate on request bodies before
+// sending them, returning *ValidationErrors without a network call when a
+// request is invalid. Validation is disabled by default.
+func WithValidation(enabled bool) ClientOption {
+	return func(c *Client) {
+		c.validate = enabled
+	}
+}
+
+// validator collects field errors found while validating a request
+type validator struct {
+	fields []*ValidationError
+}
+
+// add records an error for a field
+func (v *validator) add(field, apiField, code, message string) {
+	v.fields = append(v.fields, &ValidationError{Field: field, APIField: apiField, Code: code, Message: message})
+}
+
+// required records an error when value is empty
+func (v *validator) required(field, apiField, value string) {
+	if strings.TrimSpace(value) == "" {
+		v.add(field, apiField, "required", "is required")
+	}
+}
+
+// notEmpty records an error when a set optional value is empty
+func (v *validator) notEmpty(field, apiField string, value *string) {
+	if value != nil && strings.TrimSpace(*value) == "" {
+		v.add(field, apiField, "invalid", "must not be empty")
+	}
+}
+
+// min records an error when value is below min
+func (v *validator) min(field, apiField string, value, min int) {
+	if value < min {
+		v.add(field, apiField, "out_of_range", fmt.Sprintf("must be at least %d", min))
+	}
+}
+
+// port records an error when port is outside the valid range
+func (v *validator) port(field, apiField string, port int) {
+	if port < 1 || port > 65535 {
+		v.add(field, apiField, "out_of_range", "must be between 1 and 65535")
+	}
+}
+
+// region records an error for an unknown region
+func (v *validator) region(field, apiField string, region Region) {
+	if region != "" && !region.IsValid() {
+		v.add(field, apiField, "invalid", fmt.Sprintf("unknown region %q", region))
+	}
+}
+
+// plan records an error for an unknown plan
+func (v *validator) plan(field, apiField string, plan Plan) {
+	if plan != "" && !plan.IsValid() {
+		v.add(field, apiField, "invalid", fmt.Sprintf("unknown plan %q", plan))
+	}
+}
+
+// repositoryURL records an error when a repository URL is missing or
+// neither a URL with a host nor an SCP-style Git address
+func (v *validator) repositoryURL(field, apiField, value string) {
+	if strings.TrimSpace(value) == "" {
+		v.add(field, apiField, "required", "is required")
+		return
+	}
+
+	if strings.HasPrefix(value, "git@") && strings.Contains(value, ":") {
+		return
+	}
+
+	if u, err := url.Parse(value); err != nil || u.Scheme == "" || u.Host == "" {
+		v.add(field, apiField, "invalid", "must be a repository URL")
+	}
+}
+
+// nilRequest returns the error of a Validate method called on a nil
+// request
+func nilRequest() error {
+	return &ValidationErrors{Fields: []*ValidationError{{Code: "required", Message: "request is required"}}}
+}
+
+// err returns the collected errors, or nil when there are none
+func (v *validator) err() error {
+	if len(v.fields) == 0 {
+		return nil
+	}
+	return &ValidationErrors{Fields: v.fields}
+}
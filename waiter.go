@@ -0,0 +1,162 @@
+package sevalla
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+const (
+	// DefaultPollInterval is the default delay between two polls of a waiter
+	DefaultPollInterval = 2 * time.Second
+
+	// DefaultMaxPollInterval is the default upper bound for the poll delay
+	// when a backoff multiplier is configured
+	DefaultMaxPollInterval = 30 * time.Second
+
+	// DefaultMaxFetchErrors is the default number of consecutive temporary
+	// fetch errors a waiter keeps polling through
+	DefaultMaxFetchErrors = 5
+)
+
+// WaitOptions configures how a waiter polls a resource
+type WaitOptions[T any] struct {
+	// Timeout bounds the total time spent waiting. Zero means the waiter
+	// only stops when the context is done.
+	Timeout time.Duration
+
+	// PollInterval is the delay before the second poll. Defaults to
+	// DefaultPollInterval.
+	PollInterval time.Duration
+
+	// MaxPollInterval caps the delay between polls. Defaults to
+	// DefaultMaxPollInterval.
+	MaxPollInterval time.Duration
+
+	// Multiplier grows the delay after every poll. Values of 1 or less keep
+	// the delay fixed at PollInterval.
+	Multiplier float64
+
+	// MaxFetchErrors is the number of consecutive temporary fetch errors,
+	// such as rate limiting or a 503, after which the wait ends with the
+	// error. Defaults to DefaultMaxFetchErrors, negative values end the wait
+	// on the first error.
+	MaxFetchErrors int
+
+	// OnTransition is called with the resource the first time it is
+	// fetched and every time its state changes afterwards.
+	OnTransition func(T)
+}
+
+// Waiter polls a resource until a condition is met. The service waiters
+// such as DeploymentsService.WaitForDeployment are built on it, and it can
+// be used directly to wait on custom conditions.
+type Waiter[T any] struct {
+	// Fetch retrieves the current version of the resource
+	Fetch func(ctx context.Context) (T, error)
+
+	// State returns the state used to detect transitions. When nil,
+	// OnTransition is only called for the first fetch.
+	State func(T) string
+
+	// Done reports whether polling should stop. A non-nil error ends the
+	// wait early, typically because the resource reached a failure state.
+	Done func(T) (bool, error)
+}
+
+// Wait polls until Done reports true, Fetch fails, the timeout elapses or
+// the context is done. Temporary fetch errors, as reported by IsTemporary,
+// are polled through up to MaxFetchErrors times in a row, waiting at least
+// as long as a rate limited response asks. It always returns the last
+// fetched resource.
+func (w *Waiter[T]) Wait(ctx context.Context, opts *WaitOptions[T]) (T, error) {
+	if opts == nil {
+		opts = &WaitOptions[T]{}
+	}
+
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	interval := opts.PollInterval
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+
+	maxInterval := opts.MaxPollInterval
+	if maxInterval <= 0 {
+		maxInterval = DefaultMaxPollInterval
+	}
+
+	maxFetchErrors := opts.MaxFetchErrors
+	if maxFetchErrors == 0 {
+		maxFetchErrors = DefaultMaxFetchErrors
+	}
+
+	var (
+		last        T
+		state       string
+		first       = true
+		fetchErrors int
+	)
+
+	for {
+		current, err := w.Fetch(ctx)
+		if err != nil {
+			fetchErrors++
+			if !IsTemporary(err) || fetchErrors > maxFetchErrors {
+				return last, err
+			}
+
+			delay := interval
+			var rateErr *RateLimitError
+			if errors.As(err, &rateErr) && rateErr.Rate.RetryAfter > delay {
+				delay = rateErr.Rate.RetryAfter
+			}
+			if !sleepContext(ctx, delay) {
+				// The deadline leaves no time for another poll
+				if ctx.Err() != nil {
+					return last, ctx.Err()
+				}
+				return last, err
+			}
+			continue
+		}
+		last = current
+		fetchErrors = 0
+
+		var s string
+		if w.State != nil {
+			s = w.State(current)
+		}
+
+		if first || s != state {
+			first = false
+			state = s
+			if opts.OnTransition != nil {
+				opts.OnTransition(current)
+			}
+		}
+
+		if done, err := w.Done(current); done || err != nil {
+			return current, err
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return last, ctx.Err()
+		case <-timer.C:
+		}
+
+		if opts.Multiplier > 1 {
+			interval = time.Duration(float64(interval) * opts.Multiplier)
+			if interval > maxInterval {
+				interval = maxInterval
+			}
+		}
+	}
+}